@@ -1,89 +1,1230 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/local/sci-scraper/internal/metrics"
 	"github.com/local/sci-scraper/internal/scraper"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/time/rate"
+)
+
+// Exit codes of the CLI.
+const (
+	exitOK        = 0 // every year scraped
+	exitError     = 1 // -selftest, -verify or -merge-files failed, or the run could not start
+	exitPartial   = 2 // some years failed
+	exitAllFailed = 3 // every year attempted failed
+	exitUsage     = 4 // bad arguments or config
+	exitDrift     = 5 // every year scraped, but -baseline found more changes than allowed
 )
 
 func main() {
-	year := flag.Int("year", 0, "Single year to scrape (overrides from/to)")
-	from := flag.Int("from", 2017, "Start year to scrape (inclusive)")
-	to := flag.Int("to", 2018, "End year to scrape (inclusive)")
-	out := flag.String("out", "./output", "Output directory for JSON files")
-	concurrency := flag.Int("concurrency", 1, "Number of concurrent workers to run")
-	retries := flag.Int("retries", 0, "Number of times to retry a failed year")
-	retryDelay := flag.Int("retry-delay", 2, "Delay in seconds between retries")
-	flag.Parse()
+	os.Exit(run(os.Args[1:]))
+}
 
-	years := []int{}
-	if *year != 0 {
-		years = append(years, *year)
+// run is the CLI given its arguments, returning the exit code.
+func run(args []string) int {
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	year := flags.Int("year", 0, "Single year to scrape (overrides from/to)")
+	from := flags.Int("from", 2017, "Start year to scrape (inclusive)")
+	to := flags.Int("to", 2018, "End year to scrape (inclusive)")
+	out := flags.String("out", "./output", "Output directory for JSON files, or - to write to stdout")
+	concurrency := flags.Int("concurrency", 1, "Number of concurrent workers to run")
+	pageConcurrency := flags.Int("page-concurrency", 1, "How many of a paginated year's listed result pages to fetch at once")
+	retries := flags.Int("retries", 0, "Number of times to retry a failed year")
+	retryDelay := flags.Int("retry-delay", 2, "Base delay in seconds between retries, doubled per attempt")
+	pageRetries := flags.Int("page-retries", 2, "Number of times to retry a failed later result page of a year before writing the pages read so far as partial")
+	maxRetryDelay := flags.Int("max-retry-delay", 60, "Upper bound in seconds for the retry delay")
+	multilang := flags.Bool("multilang", false, "Capture English and Hindi summaries into summary_en/summary_hi when present")
+	stamp := flags.Bool("stamp", false, "Include the source page's Last-Modified header as source_last_modified")
+	sortBy := flags.String("sort", "", "Sort each year's judgments before writing: date or title")
+	dateRange := flags.String("date-range", "", "Scrape only judgments dated FROM,TO (YYYY-MM-DD,YYYY-MM-DD, inclusive), as -from-date and -to-date over the years they span; overrides year/from/to")
+	subjectsOnly := flags.Bool("subjects", false, "Write only the sorted, de-duplicated subjects of the scraped years to subjects.json")
+	serialInterval := flags.Int("serial-interval", 0, "Milliseconds between requests, made strictly one at a time; forces concurrency, -page-concurrency, -pdf-concurrency and -verify-concurrency to 1 and overrides other pacing")
+	redact := flags.Bool("redact", false, "Replace party names in cause titles, keeping case numbers")
+	redactWith := flags.String("redact-with", "[REDACTED]", "Replacement text used by -redact")
+	rawText := flags.Bool("raw-text", false, "Keep subject and summary whitespace as on the page instead of collapsing it")
+	fixHTML := flags.String("fix-html", "", "Comma-separated HTML fixers applied before parsing: comments, forms")
+	pdfListFile := flags.String("pdf-list-file", "", "Write every distinct PDF link of the scraped years to this file (JSON if it ends in .json, else one per line)")
+	serialColumn := flags.String("serial-column", "auto", "Position of the table's serial-number column: auto, first, last or none")
+	timeout := flags.Int("timeout", 0, "Seconds allowed per year before it is abandoned (0 = no limit)")
+	baseURL := flags.String("base-url", scraper.DefaultBaseURL, "Landmark judgment summaries page to scrape (e.g. a mirror)")
+	userAgent := flags.String("user-agent", scraper.DefaultUserAgent, "User-Agent header sent with every request")
+	format := flags.String("format", "json", "Output format: json, ndjson, csv or sqlite")
+	dedup := flags.Bool("dedup", false, "Drop duplicate judgments (same PDF link, or same date and cause title) before writing")
+	downloadPDFs := flags.Bool("download-pdfs", false, "Also download each judgment's PDF into <out>/pdfs/<year>/, recording its path as pdf_path")
+	pdfConcurrency := flags.Int("pdf-concurrency", scraper.DefaultPDFConcurrency, "With -download-pdfs, how many PDFs of a year to download at once")
+	verifyPDFs := flags.Bool("verify-pdfs", false, "Check that each PDF link serves a PDF and record it as pdf_ok")
+	verifyConcurrency := flags.Int("verify-concurrency", scraper.DefaultVerifyConcurrency, "With -verify-pdfs, how many PDF links of a year to check at once")
+	subject := flags.String("subject", "", "Keep only judgments whose subject contains this keyword (case-insensitive)")
+	fromDate := flags.String("from-date", "", "Drop judgments dated before this day (YYYY-MM-DD)")
+	toDate := flags.String("to-date", "", "Drop judgments dated after this day (YYYY-MM-DD)")
+	dropUnparsedDates := flags.Bool("drop-unparsed-dates", false, "With -from-date/-to-date, also drop judgments whose date cannot be parsed")
+	limit := flags.Int("limit", 0, "Keep only the first N judgments of each year, for sampling (0 = all)")
+	requirePDF := flags.Bool("require-pdf", false, "Drop judgments without a PDF link")
+	onlyMissingPDF := flags.Bool("only-missing-pdf", false, "Keep only judgments without a PDF link, to chase down gaps")
+	keepPartial := flags.Bool("keep-partial", false, "On SIGINT/SIGTERM or -timeout, still write the judgments an interrupted year had read (replacing its earlier file)")
+	merge := flags.Bool("merge", false, "Also write every scraped judgment, tagged with its year, to sci_judgments_all.json, streamed in the order years finish")
+	mergeOnly := flags.Bool("merge-only", false, "Like -merge, but skip the per-year files")
+	rps := flags.Float64("rps", 1, "Maximum requests per second across all workers (0 = unlimited)")
+	minDelay := flags.Duration("min-delay", 0, "Minimum time between requests across all workers, e.g. 2s; the stricter of -rps and -min-delay applies")
+	appendOut := flags.Bool("append", false, "Merge each year's judgments into its existing output file, dropping duplicates, instead of replacing it")
+	skipExisting := flags.Bool("skip-existing", false, "Skip years whose output already exists and is valid")
+	force := flags.Bool("force", false, "Scrape every year even with -skip-existing or -resume")
+	trackingParams := flags.String("tracking-params", strings.Join(scraper.DefaultTrackingParams, ","), "Comma-separated query parameters stripped from PDF links (utm_* always are)")
+	outTemplate := flags.String("out-template", "", "Template for each year's file path under -out, using {{.Year}} and {{.Format}} (default sci_judgments_{{.Year}}.{{.Format}})")
+	yearList := flags.String("years", "", "Comma-separated years and ranges to scrape, e.g. 2017,2019-2021 (overrides year/from/to)")
+	latest := flags.Bool("latest", false, "Scrape only the latest year allowed (-max-year, default the current year) and merge it into its file as with -append; overrides from/to")
+	parserName := flags.String("parser", "", "Parse pages with this registered parser (default: detect the layout, falling back to "+scraper.DefaultParser+")")
+	mergeFiles := flags.String("merge-files", "", "Combine these comma-separated JSON/NDJSON files or globs, deduplicated and sorted, into "+scraper.MergedFileName+" under -out, without scraping")
+	saveHTML := flags.String("save-html", "", "Keep each fetched page as <year>.html (<year>-p<n>.html for later result pages) in this directory, with its URL and fetch time in <year>.meta.json")
+	checksum := flags.Bool("checksum", false, "Write a .sha256 sidecar next to each per-year output file")
+	verify := flags.Bool("verify", false, "Check the files under -out against their .sha256 sidecars, without scraping, and exit non-zero on any mismatch")
+	selftest := flags.Bool("selftest", false, "Fetch one recent year (-year, default last year), check the page still parses as expected, and exit non-zero if not")
+	selftestMin := flags.Int("selftest-min", 5, "With -selftest, the fewest judgments the page must yield")
+	selftestComplete := flags.Float64("selftest-complete", 0.8, "With -selftest, the share of judgments that must have a date, cause title and PDF link")
+	parseFile := flags.String("parse-file", "", "Parse this saved HTML page as -year instead of fetching it (links resolve against -base-url)")
+	strict := flags.Bool("strict", false, "Fail a year instead of writing it when its judgments look broken (no rows, or rows without date, cause title and PDF link)")
+	delay := flags.Int("delay", 0, "Milliseconds to pause between one year and the next, in each worker")
+	metricsAddr := flags.String("metrics-addr", "", "Serve Prometheus metrics on this address (e.g. :9090) at /metrics while running")
+	resumeFile := flags.String("resume", "", "Record completed years in this JSON state file and skip them on later runs")
+	incremental := flags.String("incremental", "", "Record the judgments written in this JSON state file and write only those not seen by earlier runs; each year file then holds just this run's new judgments, empty when there are none")
+	full := flags.Bool("full", false, "With -incremental, write every judgment and rebuild the state file from this run")
+	columnMap := flags.String("column-map", "", "Comma-separated header=field pairs routing columns with unrecognized headers, e.g. Particulars=subject")
+	quiet := flags.Bool("quiet", false, "Log only warnings and errors; print the run summary only if a year failed")
+	verbose := flags.Bool("verbose", false, "Also log request URLs, cache use and parse statistics")
+	cacheDir := flags.String("cache-dir", "", "Cache each raw page fetched in this directory, keyed by URL, and reuse it while fresh (revalidating stale pages with ETag/If-Modified-Since)")
+	cacheTTL := flags.Duration("cache-ttl", 24*time.Hour, "How long a cached page stays fresh (0 = forever)")
+	noCache := flags.Bool("no-cache", false, "Fetch every page afresh, ignoring (but refreshing) the cache")
+	minYear := flags.Int("min-year", scraper.DefaultMinYear, "Earliest year that may be scraped")
+	maxYear := flags.Int("max-year", 0, "Latest year that may be scraped (0 = the current year)")
+	dbPath := flags.String("db", "", "With -format sqlite, the database file to upsert into (default "+scraper.SQLiteFileName+" under -out)")
+	csvBOM := flags.Bool("csv-bom", false, "With -format csv, start each file with a UTF-8 byte order mark for Excel")
+	gzipOut := flags.Bool("gzip", false, "Compress the per-year output files, appending .gz to their names")
+	withHash := flags.Bool("hash", false, "Record each judgment's content hash as hash, for change tracking")
+	baseline := flags.String("baseline", "", "Compare each year scraped with its file in this directory from an earlier run, print what was added, removed and changed, and exit 5 if that exceeds -max-added, -max-removed or -max-changed")
+	maxAdded := flags.Int("max-added", 0, "With -baseline, the judgments that may be added before the run fails (negative = no limit)")
+	maxRemoved := flags.Int("max-removed", 0, "With -baseline, the judgments that may be removed before the run fails (negative = no limit)")
+	maxChanged := flags.Int("max-changed", 0, "With -baseline, the judgments that may change before the run fails (negative = no limit)")
+	dryRun := flags.Bool("dry-run", false, "Fetch and parse, report each year's judgment count, but write no files")
+	headers := map[string]string{}
+	flags.Func("header", "Send this `key=value` header with every request (repeatable); a User-Agent here overrides -user-agent", func(v string) error {
+		k, val, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return errors.New("want key=value")
+		}
+		headers[http.CanonicalHeaderKey(strings.TrimSpace(k))] = strings.TrimSpace(val)
+		return nil
+	})
+	var cookies []*http.Cookie
+	flags.Func("cookie", "Send this `name=value` cookie with every request to the site (repeatable)", func(v string) error {
+		c, err := http.ParseCookie(v)
+		if err != nil {
+			return err
+		}
+		cookies = append(cookies, c...)
+		return nil
+	})
+	configPath := flags.String("config", "", "JSON file of settings (year, from, to, out, concurrency, retries, rps, user_agent, base_url, format, min_year, max_year, column_map); flags given on the command line win")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	// settings come from flags, then SCI_* variables, then the config file
+	var cfg scraper.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = scraper.LoadConfig(*configPath); err != nil {
+			return usageError("%v", err)
+		}
+	}
+	cfg, err := scraper.LoadEnv(cfg)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	applyConfig(flags, cfg)
+
+	if *latest {
+		if *year != 0 || *yearList != "" || *dateRange != "" {
+			return usageError("-latest cannot be combined with -year, -years or -date-range")
+		}
+		// the daily refresh merges new judgments into the year's file;
+		// SQLite always merges
+		if scraper.Format(*format) != scraper.FormatSQLite {
+			*appendOut = true
+		}
+	}
+
+	if *serialInterval > 0 {
+		*concurrency = 1
+	}
+
+	sc := &scraper.Scraper{BaseURL: *baseURL, UserAgent: *userAgent, MultiLang: *multilang, Stamp: *stamp, RawText: *rawText, SubjectFilter: *subject, DownloadPDFs: *downloadPDFs, VerifyPDFs: *verifyPDFs, Strict: *strict}
+
+	if *fromDate != "" {
+		t, err := time.Parse("2006-01-02", *fromDate)
+		if err != nil {
+			return usageError("invalid -from-date: %v", err)
+		}
+		sc.FromDate = t
+	}
+	if *toDate != "" {
+		t, err := time.Parse("2006-01-02", *toDate)
+		if err != nil {
+			return usageError("invalid -to-date: %v", err)
+		}
+		sc.ToDate = t
+	}
+	sc.DropUnparsedDates = *dropUnparsedDates
+	if *limit < 0 {
+		return usageError("-limit must not be negative")
+	}
+	sc.Limit = *limit
+	sc.KeepPartial = *keepPartial
+	switch {
+	case *requirePDF && *onlyMissingPDF:
+		return usageError("-require-pdf and -only-missing-pdf cannot be combined")
+	case *requirePDF:
+		sc.PDFFilter = scraper.PDFRequired
+	case *onlyMissingPDF:
+		sc.PDFFilter = scraper.PDFMissing
+	}
+	sc.MinYear, sc.MaxYear = *minYear, *maxYear
+	sc.TrackingParams = []string{}
+	for _, p := range strings.Split(*trackingParams, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			sc.TrackingParams = append(sc.TrackingParams, p)
+		}
+	}
+	sc.CacheDir, sc.CacheTTL, sc.RefreshCache = *cacheDir, *cacheTTL, *noCache
+
+	if *minDelay < 0 {
+		return usageError("-min-delay must not be negative")
+	}
+	if *serialInterval > 0 {
+		// one request at a time, pages, PDF checks and downloads alike
+		sc.Limiter = rate.NewLimiter(rate.Every(time.Duration(*serialInterval)*time.Millisecond), 1)
 	} else {
-		for y := *from; y <= *to; y++ {
-			years = append(years, y)
+		limit := rate.Limit(*rps)
+		if *minDelay > 0 && (limit <= 0 || rate.Every(*minDelay) < limit) {
+			limit = rate.Every(*minDelay)
+		}
+		if limit > 0 {
+			sc.Limiter = rate.NewLimiter(limit, 1)
 		}
 	}
 
-	// If concurrency is 1, just run sequentially (simple path)
-	if *concurrency <= 1 {
-		for _, y := range years {
-			fmt.Printf("Scraping year %d -> output dir %s\n", y, *out)
-			if err := scraper.ScrapeYear(y, filepath.Clean(*out)); err != nil {
-				log.Printf("scrape failed for %d: %v", y, err)
-			} else {
-				fmt.Printf("Done year %d\n", y)
+	if *parserName != "" && !slices.Contains(scraper.Parsers(), *parserName) {
+		return usageError("unknown -parser %q; registered parsers: %s", *parserName, strings.Join(scraper.Parsers(), ", "))
+	}
+	sc.Parser = *parserName
+	sc.Checksum = *checksum
+	if *pdfConcurrency < 1 {
+		return usageError("-pdf-concurrency must be at least 1")
+	}
+	sc.PDFConcurrency = *pdfConcurrency
+	if *verifyConcurrency < 1 {
+		return usageError("-verify-concurrency must be at least 1")
+	}
+	sc.VerifyConcurrency = *verifyConcurrency
+	if *pageConcurrency < 1 {
+		return usageError("-page-concurrency must be at least 1")
+	}
+	sc.PageConcurrency = *pageConcurrency
+	if *serialInterval > 0 {
+		sc.PDFConcurrency, sc.VerifyConcurrency, sc.PageConcurrency = 1, 1, 1
+	}
+	sc.SaveHTMLDir = *saveHTML
+	if len(headers) > 0 {
+		sc.RequestHeaders = headers
+	}
+	sc.Cookies = cookies
+
+	switch f := scraper.Format(*format); f {
+	case scraper.FormatJSON, scraper.FormatNDJSON, scraper.FormatCSV, scraper.FormatSQLite:
+		sc.Format = f
+		if *gzipOut && f == scraper.FormatSQLite {
+			return usageError("-gzip cannot be combined with -format sqlite")
+		}
+		if *dbPath != "" && f != scraper.FormatSQLite {
+			return usageError("-db needs -format sqlite")
+		}
+		if *appendOut && f == scraper.FormatSQLite {
+			return usageError("-append cannot be combined with -format sqlite, which always merges")
+		}
+		if *baseline != "" && f == scraper.FormatSQLite {
+			return usageError("-baseline cannot be combined with -format sqlite")
+		}
+		sc.Gzip = *gzipOut
+		sc.CSVBOM = *csvBOM
+		sc.DBPath = *dbPath
+		sc.FileTemplate = *outTemplate
+		if _, err := sc.YearFile(scraper.DefaultMinYear); err != nil {
+			return usageError("invalid -out-template: %v", err)
+		}
+	default:
+		return usageError("unknown -format value %q (want json, ndjson, csv or sqlite)", *format)
+	}
+
+	switch *serialColumn {
+	case "auto":
+		sc.SerialColumn = scraper.SerialAuto
+	case "first":
+		sc.SerialColumn = scraper.SerialFirst
+	case "last":
+		sc.SerialColumn = scraper.SerialLast
+	case "none":
+		sc.SerialColumn = scraper.SerialNone
+	default:
+		return usageError("unknown -serial-column value %q (want auto, first, last or none)", *serialColumn)
+	}
+
+	if *columnMap != "" {
+		sc.ColumnMap = scraper.ColumnMap{}
+		for _, pair := range strings.Split(*columnMap, ",") {
+			header, field, ok := strings.Cut(pair, "=")
+			if !ok {
+				return usageError("invalid -column-map entry %q (want header=field)", pair)
 			}
+			sc.ColumnMap[header] = field
+		}
+		if err := sc.ColumnMap.Validate(); err != nil {
+			return usageError("%v", err)
 		}
-		return
 	}
 
-	// Worker pool for concurrent scraping
-	type job struct{ year int }
-	jobs := make(chan job)
-	var wg sync.WaitGroup
+	if *fixHTML != "" {
+		var fixers []scraper.PreprocessFunc
+		for _, name := range strings.Split(*fixHTML, ",") {
+			switch strings.TrimSpace(name) {
+			case "comments":
+				fixers = append(fixers, scraper.StripComments)
+			case "forms":
+				fixers = append(fixers, scraper.StripForms)
+			default:
+				return usageError("unknown -fix-html fixer %q (want comments or forms)", name)
+			}
+		}
+		sc.Preprocess = scraper.ChainPreprocess(fixers...)
+	}
 
-	worker := func(id int) {
-		defer wg.Done()
-		for j := range jobs {
-			attempt := 0
-			for {
-				attempt++
-				fmt.Printf("worker %d: scraping %d (attempt %d)\n", id, j.year, attempt)
-				err := scraper.ScrapeYear(j.year, filepath.Clean(*out))
-				if err == nil {
-					fmt.Printf("worker %d: done %d\n", id, j.year)
-					break
+	// post-processing applied to each year's judgments before writing
+	var steps []func([]scraper.Judgment) []scraper.Judgment
+	years := []int{}
+	if *dateRange != "" {
+		rangeFrom, rangeTo, err := parseDateRange(*dateRange)
+		if err != nil {
+			return usageError("invalid -date-range: %v", err)
+		}
+		if *fromDate != "" || *toDate != "" {
+			return usageError("-date-range cannot be combined with -from-date or -to-date")
+		}
+		for y := rangeFrom.Year(); y <= rangeTo.Year(); y++ {
+			years = append(years, y)
+		}
+		// the range filters rows as they are parsed, like -from-date/-to-date,
+		// so that -limit counts only judgments in range
+		sc.FromDate, sc.ToDate = rangeFrom, rangeTo
+	}
+	// dropped as rows are parsed, like the filters, so that -limit counts
+	// only judgments that are written
+	sc.Dedup = *dedup
+	if *redact {
+		steps = append(steps, func(js []scraper.Judgment) []scraper.Judgment {
+			for i := range js {
+				js[i].CauseTitleCaseNo = scraper.RedactCauseTitle(js[i].CauseTitleCaseNo, *redactWith)
+			}
+			return js
+		})
+	}
+	if *withHash {
+		steps = append(steps, func(js []scraper.Judgment) []scraper.Judgment {
+			for i := range js {
+				js[i].ContentHash = js[i].Hash()
+			}
+			return js
+		})
+	}
+	switch *sortBy {
+	case "":
+	case "date":
+		steps = append(steps, inPlace(scraper.SortByDate))
+	case "title":
+		steps = append(steps, inPlace(scraper.SortByCauseTitle))
+	default:
+		return usageError("unknown -sort value %q (want date or title)", *sortBy)
+	}
+	// with -out - the judgments go to stdout and progress to stderr
+	toStdout := *out == "-"
+	var progress io.Writer = os.Stdout
+	if toStdout {
+		if *downloadPDFs {
+			return usageError("-download-pdfs needs an output directory, not -out -")
+		}
+		if sc.Format == scraper.FormatSQLite {
+			return usageError("-format sqlite needs an output directory, not -out -")
+		}
+		if *gzipOut {
+			return usageError("-gzip needs an output directory, not -out -")
+		}
+		if *appendOut {
+			return usageError("-append needs an output directory, not -out -")
+		}
+		progress = os.Stderr
+	}
+	sc.Logger = log.New(progress, "", 0)
+	if *metricsAddr != "" {
+		reg := prometheus.NewRegistry()
+		sc.Metrics = metrics.New(reg)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+		go func() {
+			if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+				log.Printf("serving metrics: %v", err)
+			}
+		}()
+	}
+	switch {
+	case *quiet && *verbose:
+		return usageError("-quiet and -verbose cannot be combined")
+	case *quiet:
+		sc.LogLevel = scraper.LogQuiet
+	case *verbose:
+		sc.LogLevel = scraper.LogVerbose
+	}
+	var stdoutMu sync.Mutex
+	persist := func(y int, js []scraper.Judgment) error {
+		if toStdout {
+			stdoutMu.Lock()
+			defer stdoutMu.Unlock()
+			return sc.Encode(os.Stdout, js)
+		}
+		if *appendOut {
+			added, err := sc.AppendYear(filepath.Clean(*out), y, js)
+			if err == nil {
+				sc.Logf(scraper.LogNormal, "year %d: %d new judgments appended", y, added)
+			}
+			return err
+		}
+		return sc.WriteYear(filepath.Clean(*out), y, js)
+	}
+	if *subjectsOnly || *mergeOnly {
+		persist = func(int, []scraper.Judgment) error { return nil }
+	}
+	if *dryRun {
+		sc.DownloadPDFs = false
+		persist = func(y int, js []scraper.Judgment) error {
+			sc.Logger.Printf("year %d: %d judgments (dry run, nothing written)", y, len(js))
+			return nil
+		}
+	}
+	*merge = *merge || *mergeOnly
+	if *merge && toStdout {
+		return usageError("-merge needs an output directory, not -out -")
+	}
+	// reports spanning the whole run, built as each year is written: the
+	// merged file is streamed, and only distinct subjects and PDF links are
+	// kept in memory
+	var (
+		reportMu    sync.Mutex
+		subjectRows []scraper.Judgment
+		pdfLinks    []string
+		mergedYears = map[int]bool{}
+		merged      *scraper.MergedWriter
+	)
+	if !*dryRun && (*subjectsOnly || *pdfListFile != "" || *merge) {
+		if *merge {
+			merged = scraper.NewMergedWriter(filepath.Clean(*out))
+		}
+		write := persist
+		persist = func(y int, js []scraper.Judgment) error {
+			if err := write(y, js); err != nil {
+				return err
+			}
+			reportMu.Lock()
+			defer reportMu.Unlock()
+			for _, s := range scraper.UniqueSubjects(js) {
+				subjectRows = append(subjectRows, scraper.Judgment{Subject: s})
+			}
+			pdfLinks = append(pdfLinks, scraper.PDFLinks(js)...)
+			// a year retried after its PDF downloads failed is written again
+			if merged != nil && !mergedYears[y] {
+				mergedYears[y] = true
+				return merged.Add(y, js)
+			}
+			return nil
+		}
+		defer func() {
+			if *subjectsOnly {
+				if err := writeSubjects(*out, scraper.UniqueSubjects(subjectRows)); err != nil {
+					log.Printf("writing subjects: %v", err)
+				}
+			}
+			if *pdfListFile != "" {
+				slices.Sort(pdfLinks)
+				if err := writePDFList(*pdfListFile, slices.Compact(pdfLinks)); err != nil {
+					log.Printf("writing PDF list: %v", err)
+				}
+			}
+			if merged != nil {
+				if err := merged.Close(); err != nil {
+					log.Printf("writing merged file: %v", err)
 				}
-				log.Printf("worker %d: error scraping %d: %v", id, j.year, err)
-				if attempt > *retries {
-					log.Printf("worker %d: giving up on %d after %d attempts", id, j.year, attempt)
-					break
+			}
+		}()
+	}
+	// judgments written by earlier runs, for -incremental
+	var seen *scraper.SeenState
+	if *full && *incremental == "" {
+		return usageError("-full needs -incremental")
+	}
+	if *incremental != "" {
+		var err error
+		if seen, err = scraper.LoadSeenState(*incremental); err != nil {
+			return fatal("reading incremental state: %v", err)
+		}
+		if !*full {
+			sc.Seen = seen
+		}
+	}
+	// judgments persisted per year, for the run summary, and with
+	// -incremental those to record as seen once the year has succeeded: a
+	// year retried after its PDF downloads failed must see them as new again
+	counts := map[int]int{}
+	pendingSeen := map[int][]scraper.Judgment{}
+	// with -baseline, the judgments persisted per year, to diff once the
+	// run is over, and each year's baseline as read before the first
+	// attempt wrote it, so that -baseline may be the output directory
+	scraped := map[int][]scraper.Judgment{}
+	baselines := map[int][]scraper.Judgment{}
+	var countsMu sync.Mutex
+	// the steps run before PDFs are checked or downloaded, so that those
+	// only see the judgments kept
+	sc.Transform = func(y int, js []scraper.Judgment) []scraper.Judgment {
+		for _, step := range steps {
+			js = step(js)
+		}
+		return js
+	}
+	sc.PersistFunc = func(y int, js []scraper.Judgment) error {
+		countsMu.Lock()
+		_, read := baselines[y]
+		countsMu.Unlock()
+		var old []scraper.Judgment
+		if *baseline != "" && !read {
+			var err error
+			if old, err = readBaseline(sc, filepath.Clean(*baseline), y); err != nil {
+				return fmt.Errorf("reading -baseline: %w", err)
+			}
+		}
+		countsMu.Lock()
+		counts[y] = len(js)
+		if *baseline != "" {
+			scraped[y] = js
+			if !read {
+				baselines[y] = old
+			}
+		}
+		countsMu.Unlock()
+		if len(js) == 0 {
+			if seen != nil && !*full {
+				// a year file holds one run's new judgments, so with none it
+				// is emptied rather than left holding the previous run's
+				if !toStdout && !*appendOut && sc.Format != scraper.FormatSQLite {
+					sc.Logf(scraper.LogNormal, "year %d: no new judgments, emptying its file", y)
+					return persist(y, []scraper.Judgment{})
 				}
-				time.Sleep(time.Duration(*retryDelay) * time.Second)
+				sc.Logf(scraper.LogNormal, "year %d: no new judgments, nothing written", y)
+			} else {
+				sc.Logf(scraper.LogNormal, "year %d: no judgments in range, nothing written", y)
+			}
+			return nil
+		}
+		if err := persist(y, js); err != nil {
+			return err
+		}
+		if seen != nil && !*dryRun {
+			countsMu.Lock()
+			pendingSeen[y] = js
+			countsMu.Unlock()
+		}
+		return nil
+	}
+
+	if *mergeFiles != "" {
+		if toStdout {
+			return usageError("-merge-files needs an output directory, not -out -")
+		}
+		var paths []string
+		for _, pattern := range strings.Split(*mergeFiles, ",") {
+			matches, err := filepath.Glob(strings.TrimSpace(pattern))
+			if err != nil {
+				return usageError("invalid -merge-files pattern %q: %v", pattern, err)
+			}
+			if len(matches) == 0 {
+				return usageError("-merge-files: no files match %q", pattern)
+			}
+			paths = append(paths, matches...)
+		}
+		dest := filepath.Join(filepath.Clean(*out), scraper.MergedFileName)
+		if err := scraper.MergeFiles(paths, dest); err != nil {
+			return fatal("merging files: %v", err)
+		}
+		fmt.Fprintf(progress, "merged %d files into %s\n", len(paths), dest)
+		return exitOK
+	}
+
+	if *verify {
+		if toStdout {
+			return usageError("-verify needs an output directory, not -out -")
+		}
+		checked, failed, err := verifyChecksums(filepath.Clean(*out))
+		switch {
+		case err != nil:
+			return fatal("verifying checksums: %v", err)
+		case checked == 0:
+			return fatal("no %s sidecars found under %s", scraper.ChecksumSuffix, *out)
+		case failed > 0:
+			return fatal("%d of %d files failed verification", failed, checked)
+		}
+		fmt.Fprintf(progress, "%d files verified\n", checked)
+		return exitOK
+	}
+
+	if *selftest {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		stats, err := sc.SelfTest(ctx, scraper.SelfTestOptions{
+			Year:         *year,
+			MinJudgments: *selftestMin,
+			MinComplete:  *selftestComplete,
+		})
+		stop()
+		if err != nil {
+			return fatal("selftest failed: %v", err)
+		}
+		fmt.Fprintf(progress, "selftest passed: %d judgments parsed from %s\n", stats.Parsed, stats.FinalURL)
+		return exitOK
+	}
+
+	switch {
+	case *dateRange != "":
+		// years already derived from the range
+	case *latest:
+		_, maxYear := sc.YearRange()
+		years = append(years, maxYear)
+	case *yearList != "":
+		minYear, maxYear := sc.YearRange()
+		var err error
+		if years, err = parseYears(*yearList, minYear, maxYear); err != nil {
+			return usageError("invalid -years: %v", err)
+		}
+	case *year != 0:
+		years = append(years, *year)
+	default:
+		for y := *from; y <= *to; y++ {
+			years = append(years, y)
+		}
+	}
+
+	// whether this run creates files under -out
+	writesOut := !toStdout && !*dryRun
+
+	if writesOut {
+		if err := checkWritable(filepath.Clean(*out)); err != nil {
+			return fatal("output directory %s is not writable: %v", *out, err)
+		}
+	}
+
+	var summary scraper.RunSummary
+	defer func() {
+		if *quiet && summary.Count(scraper.YearFailed)+summary.Count(scraper.YearPartial) == 0 {
+			return
+		}
+		if err := summary.Print(progress); err != nil {
+			log.Printf("writing run summary: %v", err)
+		}
+	}()
+
+	if *skipExisting && !*force && !toStdout {
+		years = slices.DeleteFunc(years, func(y int) bool {
+			if sc.OutputExists(filepath.Clean(*out), y) {
+				sc.Logf(scraper.LogNormal, "skipping year %d: output already exists", y)
+				summary.Record(scraper.YearSummary{Year: y, Status: scraper.YearSkipped})
+				return true
 			}
+			return false
+		})
+	}
+
+	// years completed by earlier runs, and this one, for -resume
+	var resumed resumeState
+	if *resumeFile != "" {
+		var err error
+		if resumed, err = readResume(*resumeFile); err != nil {
+			return fatal("reading resume file: %v", err)
+		}
+		if !*force {
+			years = slices.DeleteFunc(years, func(y int) bool {
+				if slices.Contains(resumed.Completed, y) {
+					sc.Logf(scraper.LogNormal, "skipping year %d: completed in an earlier run", y)
+					summary.Record(scraper.YearSummary{Year: y, Status: scraper.YearSkipped})
+					return true
+				}
+				return false
+			})
 		}
 	}
 
-	// start workers
-	for i := 0; i < *concurrency; i++ {
-		wg.Add(1)
-		go worker(i + 1)
+	// failed years, recorded to failures.json at the end of the run
+	var failures []failure
+	var failuresMu sync.Mutex
+	addFailure := func(y int, err error, attempts int, took time.Duration) {
+		ys := scraper.YearSummary{Year: y, Status: scraper.YearFailed, Duration: took, Err: err}
+		if errors.Is(err, scraper.ErrPartial) {
+			countsMu.Lock()
+			ys.Status, ys.Judgments = scraper.YearPartial, counts[y]
+			countsMu.Unlock()
+		}
+		summary.Record(ys)
+		failuresMu.Lock()
+		defer failuresMu.Unlock()
+		failures = append(failures, failure{Year: y, Error: err.Error(), Category: errorCategory(err), Attempts: attempts})
 	}
+	defer func() {
+		if !writesOut {
+			return
+		}
+		if err := writeFailures(filepath.Clean(*out), failures); err != nil {
+			log.Printf("writing failures report: %v", err)
+		}
+	}()
 
-	// send jobs
-	go func() {
-		for _, y := range years {
-			fmt.Printf("queueing year %d\n", y)
-			jobs <- job{year: y}
+	// SIGINT/SIGTERM cancel the run: no new years start, in-flight ones are
+	// aborted, and output files are only ever replaced whole
+	rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var completed []int
+	var completedMu sync.Mutex
+	addCompleted := func(y int, took time.Duration) {
+		countsMu.Lock()
+		n := counts[y]
+		js, record := pendingSeen[y]
+		delete(pendingSeen, y)
+		countsMu.Unlock()
+		if record {
+			if *full {
+				seen.Reset(y)
+			}
+			seen.Record(y, js)
+			if err := seen.Save(*incremental); err != nil {
+				log.Printf("updating incremental state: %v", err)
+			}
+		}
+		summary.Record(scraper.YearSummary{Year: y, Status: scraper.YearOK, Judgments: n, Duration: took})
+		completedMu.Lock()
+		defer completedMu.Unlock()
+		completed = append(completed, y)
+		if *resumeFile != "" && !*dryRun {
+			resumed.add(y)
+			if err := writeResume(*resumeFile, resumed); err != nil {
+				log.Printf("updating resume file: %v", err)
+			}
+		}
+	}
+	defer func() {
+		if rootCtx.Err() != nil {
+			slices.Sort(completed)
+			log.Printf("interrupted; years completed before shutdown: %v", completed)
+		}
+		// interrupted years with -keep-partial, and years whose later
+		// result page failed
+		var partial []int
+		for _, f := range failures {
+			if f.Category == "partial" {
+				partial = append(partial, f.Year)
+			}
+		}
+		if len(partial) > 0 {
+			slices.Sort(partial)
+			log.Printf("years written with partial results, to be re-run: %v", partial)
 		}
-		close(jobs)
 	}()
 
-	wg.Wait()
+	if *parseFile != "" {
+		if *year == 0 {
+			return usageError("-parse-file needs -year")
+		}
+		start := time.Now()
+		err := parseSavedPage(rootCtx, sc, *parseFile, *baseURL, *year, filepath.Clean(*out))
+		if err != nil {
+			log.Printf("parsing %s failed: %v", *parseFile, err)
+			addFailure(*year, err, 1, time.Since(start))
+		} else {
+			addCompleted(*year, time.Since(start))
+		}
+		return exitStatus(&summary)
+	}
+
+	// every year, one worker or many, goes through the library's pool,
+	// which retries, bounds and paces each one
+	sc.Retries = *retries
+	sc.PageRetries = *pageRetries
+	sc.RetryDelay = time.Duration(*retryDelay) * time.Second
+	sc.MaxRetryDelay = time.Duration(*maxRetryDelay) * time.Second
+	sc.YearTimeout = time.Duration(*timeout) * time.Second
+	sc.YearDelay = time.Duration(*delay) * time.Millisecond
+	sc.OnYearDone = func(res scraper.YearResult) {
+		switch {
+		case errors.Is(res.Err, scraper.ErrEmptyYear):
+			sc.Logf(scraper.LogNormal, "year %d: the site lists no judgments, nothing written", res.Year)
+			addCompleted(res.Year, res.Duration)
+		case *dateRange != "" && errors.Is(res.Err, scraper.ErrNoDateMatches):
+			// a year the range only partly covers may have none in it
+			sc.Logf(scraper.LogNormal, "year %d: no judgments in -date-range, nothing written", res.Year)
+			addCompleted(res.Year, res.Duration)
+		case res.Err != nil:
+			log.Printf("scrape failed for %d after %d attempts: %v", res.Year, res.Attempts, res.Err)
+			addFailure(res.Year, res.Err, res.Attempts, res.Duration)
+		default:
+			sc.Logf(scraper.LogNormal, "Done year %d", res.Year)
+			addCompleted(res.Year, res.Duration)
+		}
+	}
+	sc.Logf(scraper.LogNormal, "Scraping %d years with %d workers -> output dir %s", len(years), max(*concurrency, 1), *out)
+	if _, err := sc.ScrapeYearsConcurrent(rootCtx, years, filepath.Clean(*out), *concurrency); err != nil {
+		return fatal("%v", err)
+	}
+	code := exitStatus(&summary)
+	if *baseline == "" || rootCtx.Err() != nil {
+		return code
+	}
+	// only years that succeeded are compared; an empty one removed all its
+	// judgments
+	byYear := map[int][]scraper.Judgment{}
+	for _, y := range completed {
+		byYear[y] = scraped[y]
+	}
+	var report bytes.Buffer
+	d, err := diffBaseline(sc, filepath.Clean(*baseline), baselines, byYear, &report)
+	if err != nil {
+		return fatal("comparing with -baseline: %v", err)
+	}
+	over := d.exceeds(*maxAdded, *maxRemoved, *maxChanged)
+	if !*quiet || over != "" {
+		progress.Write(report.Bytes())
+	}
+	if over != "" {
+		log.Printf("judgments drifted from -baseline beyond %s", over)
+		if code == exitOK {
+			return exitDrift
+		}
+	}
+	return code
+}
+
+// drift counts the judgments a run added, removed and changed against
+// -baseline.
+type drift struct {
+	added, removed, changed int
+}
+
+// exceeds returns the first threshold d goes over, as the flag and its
+// value, or "". A negative threshold is no limit.
+func (d drift) exceeds(maxAdded, maxRemoved, maxChanged int) string {
+	for _, c := range []struct {
+		flag   string
+		n, max int
+	}{{"-max-added", d.added, maxAdded}, {"-max-removed", d.removed, maxRemoved}, {"-max-changed", d.changed, maxChanged}} {
+		if c.max >= 0 && c.n > c.max {
+			return fmt.Sprintf("%s %d", c.flag, c.max)
+		}
+	}
+	return ""
+}
+
+// diffBaseline compares each year's judgments in byYear with its baseline,
+// using scraper.DiffJudgments, and writes one line per year and the totals
+// to w. Baselines not already read into baselines, for years nothing was
+// persisted for, are read from dir.
+func diffBaseline(sc *scraper.Scraper, dir string, baselines, byYear map[int][]scraper.Judgment, w io.Writer) (drift, error) {
+	var total drift
+	for _, y := range slices.Sorted(maps.Keys(byYear)) {
+		old, ok := baselines[y]
+		if !ok {
+			var err error
+			if old, err = readBaseline(sc, dir, y); err != nil {
+				return total, err
+			}
+		}
+		added, removed, changed := scraper.DiffJudgments(old, byYear[y])
+		fmt.Fprintf(w, "year %d: %d added, %d removed, %d changed\n", y, len(added), len(removed), len(changed))
+		total.added += len(added)
+		total.removed += len(removed)
+		total.changed += len(changed)
+	}
+	fmt.Fprintf(w, "baseline: %d added, %d removed, %d changed\n", total.added, total.removed, total.changed)
+	return total, nil
+}
+
+// readBaseline reads year's file in dir, which counts as empty if missing.
+func readBaseline(sc *scraper.Scraper, dir string, year int) ([]scraper.Judgment, error) {
+	js, err := sc.ReadYear(dir, year)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return js, err
+}
+
+// usageError logs a bad argument or config and returns exitUsage.
+func usageError(format string, args ...any) int {
+	log.Printf(format, args...)
+	return exitUsage
+}
+
+// fatal logs an error that ends the run and returns exitError.
+func fatal(format string, args ...any) int {
+	log.Printf(format, args...)
+	return exitError
+}
+
+// exitStatus returns the exit code for a run's outcome: exitOK without
+// failed or partial years, exitAllFailed when no year was written even in
+// part, else exitPartial.
+func exitStatus(summary *scraper.RunSummary) int {
+	switch {
+	case summary.Count(scraper.YearFailed)+summary.Count(scraper.YearPartial) == 0:
+		return exitOK
+	case summary.Count(scraper.YearOK)+summary.Count(scraper.YearPartial) == 0:
+		return exitAllFailed
+	}
+	return exitPartial
+}
+
+// verifyChecksums checks every file under dir that has a checksum sidecar,
+// printing each result, and returns how many were checked and how many did
+// not match.
+func verifyChecksums(dir string) (checked, failed int, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, scraper.ChecksumSuffix) {
+			return nil
+		}
+		file := strings.TrimSuffix(path, scraper.ChecksumSuffix)
+		checked++
+		ok, err := scraper.VerifyChecksum(file)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("%s: FAILED: %v\n", file, err)
+		case !ok:
+			failed++
+			fmt.Printf("%s: FAILED: checksum mismatch\n", file)
+		default:
+			fmt.Printf("%s: OK\n", file)
+		}
+		return nil
+	})
+	return checked, failed, err
+}
+
+// parseYears parses a -years list such as "2017,2019-2021" into sorted,
+// distinct years, each of which must lie within minYear..maxYear.
+func parseYears(spec string, minYear, maxYear int) ([]int, error) {
+	var years []int
+	for _, tok := range strings.Split(spec, ",") {
+		tok = strings.TrimSpace(tok)
+		lo, hi, isRange := strings.Cut(tok, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a year or a range like 2017-2019", tok)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || last < first {
+				return nil, fmt.Errorf("%q is not a year or a range like 2017-2019", tok)
+			}
+		}
+		if first < minYear || last > maxYear {
+			return nil, fmt.Errorf("%q is outside the supported range %d..%d", tok, minYear, maxYear)
+		}
+		for y := first; y <= last; y++ {
+			years = append(years, y)
+		}
+	}
+	slices.Sort(years)
+	return slices.Compact(years), nil
+}
+
+// parseSavedPage parses the HTML file at path as year's page and persists
+// the judgments as a fetched page's would be.
+func parseSavedPage(ctx context.Context, sc *scraper.Scraper, path, base string, year int, outDir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	js, _, err := sc.ParseHTML(f, base, year)
+	if err != nil {
+		return err
+	}
+	return sc.Persist(ctx, year, outDir, js)
+}
+
+// applyConfig sets each flag of flags that cfg provides, unless it was
+// given on the command line.
+func applyConfig(flags *flag.FlagSet, cfg scraper.Config) {
+	explicit := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	set := func(name, value string) {
+		if !explicit[name] {
+			flags.Set(name, value)
+		}
+	}
+	// years chosen on the command line, by any flag, replace the
+	// configured ones as a whole: a configured year would otherwise beat
+	// an explicit -from/-to
+	if !explicit["year"] && !explicit["from"] && !explicit["to"] && !explicit["years"] && !explicit["date-range"] && !explicit["latest"] {
+		for name, v := range map[string]int{"year": cfg.Year, "from": cfg.From, "to": cfg.To} {
+			if v != 0 {
+				flags.Set(name, strconv.Itoa(v))
+			}
+		}
+	}
+	for name, v := range map[string]int{"concurrency": cfg.Concurrency, "retries": cfg.Retries, "min-year": cfg.MinYear, "max-year": cfg.MaxYear} {
+		if v != 0 {
+			set(name, strconv.Itoa(v))
+		}
+	}
+	for name, v := range map[string]string{"out": cfg.Out, "user-agent": cfg.UserAgent, "base-url": cfg.BaseURL, "format": string(cfg.Format)} {
+		if v != "" {
+			set(name, v)
+		}
+	}
+	if cfg.RPS != nil {
+		set("rps", strconv.FormatFloat(*cfg.RPS, 'g', -1, 64))
+	}
+	if len(cfg.ColumnMap) > 0 {
+		var pairs []string
+		for _, header := range slices.Sorted(maps.Keys(cfg.ColumnMap)) {
+			pairs = append(pairs, header+"="+cfg.ColumnMap[header])
+		}
+		set("column-map", strings.Join(pairs, ","))
+	}
+}
+
+// checkWritable creates outDir if needed and probes that files can be
+// created in it, so an unwritable destination fails before any fetch.
+func checkWritable(outDir string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(outDir, ".sci-scraper-probe-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// failure describes a year that could not be scraped.
+type failure struct {
+	Year     int    `json:"year"`
+	Error    string `json:"error"`
+	Category string `json:"category"`
+	Attempts int    `json:"attempts"`
+}
+
+// errorCategory classifies err for failures.json.
+func errorCategory(err error) string {
+	var fe *scraper.FetchError
+	switch {
+	case errors.Is(err, scraper.ErrYearOutOfRange):
+		return "year_out_of_range"
+	case errors.Is(err, scraper.ErrEmptyYear):
+		return "empty_year"
+	case errors.Is(err, scraper.ErrNoJudgments):
+		return "no_judgments"
+	case errors.Is(err, scraper.ErrNoMatches):
+		return "no_matches"
+	case errors.Is(err, scraper.ErrInvalidJudgments):
+		return "invalid"
+	case errors.Is(err, scraper.ErrPartial):
+		return "partial"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &fe) && fe.StatusCode != 0:
+		return "http"
+	case errors.As(err, &fe):
+		return "network"
+	}
+	return "other"
+}
+
+// writeFailures writes failures.json in outDir, sorted by year. When there
+// are no failures it removes the file, so that one left by an earlier run
+// is not taken for this run's.
+func writeFailures(outDir string, failures []failure) error {
+	path := filepath.Join(outDir, "failures.json")
+	if len(failures) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Year < failures[j].Year })
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// resumeState is the -resume file: the years completed so far.
+type resumeState struct {
+	Completed []int `json:"completed"`
+}
+
+func (st *resumeState) add(y int) {
+	if !slices.Contains(st.Completed, y) {
+		st.Completed = append(st.Completed, y)
+		slices.Sort(st.Completed)
+	}
+}
+
+// readResume reads a resume file; a missing file is an empty state.
+func readResume(path string) (resumeState, error) {
+	var st resumeState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", path, err)
+	}
+	return st, nil
+}
+
+// writeResume replaces the resume file atomically, so that a run killed
+// mid-write leaves the previous state intact.
+func writeResume(path string, st resumeState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// writeSubjects writes the subject list to subjects.json in outDir, or to
+// stdout when outDir is "-".
+func writeSubjects(outDir string, subjects []string) error {
+	if subjects == nil {
+		subjects = []string{}
+	}
+	data, err := json.MarshalIndent(subjects, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if outDir == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	outDir = filepath.Clean(outDir)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "subjects.json"), data, 0o644)
+}
+
+// writePDFList writes links to path as a JSON array when path ends in .json,
+// otherwise one link per line.
+func writePDFList(path string, links []string) error {
+	if links == nil {
+		links = []string{}
+	}
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		b, err := json.MarshalIndent(links, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(b, '\n')
+	} else {
+		for _, l := range links {
+			data = append(data, l+"\n"...)
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// inPlace adapts an in-place slice operation to a post-processing step.
+func inPlace(fn func([]scraper.Judgment)) func([]scraper.Judgment) []scraper.Judgment {
+	return func(js []scraper.Judgment) []scraper.Judgment {
+		fn(js)
+		return js
+	}
+}
+
+// parseDateRange parses a "YYYY-MM-DD,YYYY-MM-DD" inclusive date range.
+func parseDateRange(s string) (time.Time, time.Time, error) {
+	fromStr, toStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q: want FROM,TO", s)
+	}
+	from, err := time.Parse("2006-01-02", strings.TrimSpace(fromStr))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := time.Parse("2006-01-02", strings.TrimSpace(toStr))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q: end is before start", s)
+	}
+	return from, to, nil
 }
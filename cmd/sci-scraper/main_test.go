@@ -0,0 +1,1105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// yearPage returns a year page of the judgments site with one judgment.
+func yearPage(year int) string {
+	return fmt.Sprintf(`<html><body><table>
+<tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>
+<tr><td>1</td><td>12-03-%d</td><td>A vs B</td><td>Tax</td><td>s</td><td><a href="/%d.pdf">PDF</a></td></tr>
+</table></body></html>`, year, year)
+}
+
+// newSite serves the page of each year in ok at /judgments/ and a 404 for
+// any other year, calling onRequest, if set, first.
+func newSite(t *testing.T, onRequest func(r *http.Request), ok ...int) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{}
+	for _, y := range ok {
+		pages[fmt.Sprint(y)] = yearPage(y)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		y := r.URL.Query().Get("judgment_year")
+		if onRequest != nil {
+			onRequest(r)
+		}
+		body, found := pages[y]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// readFailures returns the failures.json of dir.
+func readFailures(t *testing.T, dir string) []failure {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "failures.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []failure
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+// captureStdout returns what f writes to standard output.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	f()
+	w.Close()
+	return string(<-done)
+}
+
+func TestRunExitCodes(t *testing.T) {
+	srv := newSite(t, nil, 2018)
+	base := srv.URL + "/judgments/"
+	for _, tc := range []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"ok", []string{"-years", "2018"}, exitOK},
+		{"partial", []string{"-years", "2018,2019"}, exitPartial},
+		{"partial concurrent", []string{"-years", "2018,2019", "-concurrency", "2"}, exitPartial},
+		{"all failed", []string{"-years", "2019"}, exitAllFailed},
+		{"unknown format", []string{"-years", "2018", "-format", "xml"}, exitUsage},
+		{"unknown flag", []string{"-no-such-flag"}, exitUsage},
+		{"negative min delay", []string{"-years", "2018", "-min-delay", "-1s"}, exitUsage},
+		{"full without incremental", []string{"-years", "2018", "-full"}, exitUsage},
+		{"date range with from-date", []string{"-date-range", "2018-01-01,2018-06-30", "-from-date", "2018-02-01"}, exitUsage},
+		{"no verify workers", []string{"-years", "2018", "-verify-pdfs", "-verify-concurrency", "0"}, exitUsage},
+		{"no page workers", []string{"-years", "2018", "-page-concurrency", "0"}, exitUsage},
+		{"latest with years", []string{"-latest", "-years", "2018"}, exitUsage},
+		{"latest to stdout", []string{"-latest", "-max-year", "2018", "-out", "-"}, exitUsage},
+		{"baseline with sqlite", []string{"-years", "2018", "-format", "sqlite", "-baseline", "base"}, exitUsage},
+		{"db without sqlite", []string{"-years", "2018", "-db", "j.db"}, exitUsage},
+		{"conflicting PDF filters", []string{"-years", "2018", "-require-pdf", "-only-missing-pdf"}, exitUsage},
+		{"only missing PDFs", []string{"-years", "2018", "-only-missing-pdf"}, exitAllFailed},
+		{"help", []string{"-h"}, exitOK},
+		{"verify without sidecars", []string{"-verify"}, exitError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := t.TempDir()
+			args := append([]string{"-base-url", base, "-out", out, "-rps", "0", "-quiet"}, tc.args...)
+			if got := run(args); got != tc.want {
+				t.Errorf("run(%q) = %d, want %d", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunWritesYearsAndFailures(t *testing.T) {
+	srv := newSite(t, nil, 2018)
+	out := t.TempDir()
+	run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2018,2019"})
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2018.json")); err != nil {
+		t.Errorf("the scraped year was not written: %v", err)
+	}
+	if got := readFailures(t, out); len(got) != 1 || got[0].Year != 2019 || got[0].Category != "http" {
+		t.Errorf("failures.json = %+v, want 2019 failed with an HTTP error", got)
+	}
+}
+
+func TestRunInterruptedFailsUnstartedYears(t *testing.T) {
+	var interrupted atomic.Bool
+	srv := newSite(t, func(r *http.Request) {
+		if !interrupted.Swap(true) {
+			syscall.Kill(os.Getpid(), syscall.SIGINT)
+			// hold the first year until the interrupt aborts it
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}, 2017, 2018, 2019)
+	out := t.TempDir()
+	code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2017-2019"})
+	if code == exitOK {
+		t.Errorf("an interrupted run exited %d", code)
+	}
+	failed := map[int]string{}
+	for _, f := range readFailures(t, out) {
+		failed[f.Year] = f.Category
+	}
+	for _, y := range []int{2018, 2019} {
+		if failed[y] != "canceled" {
+			t.Errorf("year %d, never started, recorded as %q, want canceled", y, failed[y])
+		}
+	}
+}
+
+func TestWriteFailures(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "failures.json")
+	if err := writeFailures(dir, []failure{{Year: 2019, Error: "b"}, {Year: 2017, Error: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFailures(t, dir); len(got) != 2 || got[0].Year != 2017 || got[1].Year != 2019 {
+		t.Errorf("failures.json = %+v, want 2017 then 2019", got)
+	}
+
+	if err := writeFailures(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("failures.json survived a run without failures: %v", err)
+	}
+	if err := writeFailures(dir, nil); err != nil {
+		t.Errorf("no failures and no file: %v", err)
+	}
+}
+
+func TestRunSkipExisting(t *testing.T) {
+	var requests atomic.Int32
+	srv := newSite(t, func(*http.Request) { requests.Add(1) }, 2017, 2018)
+	out := t.TempDir()
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2017,2018", "-skip-existing"}
+	if err := os.WriteFile(filepath.Join(out, "sci_judgments_2017.json"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run(args); code != exitOK || requests.Load() != 2 {
+		t.Fatalf("first run: exit %d after %d requests; want the empty 2017 file redone too", code, requests.Load())
+	}
+	requests.Store(0)
+	if code := run(args); code != exitOK || requests.Load() != 0 {
+		t.Errorf("second run: exit %d after %d requests, want every year skipped", code, requests.Load())
+	}
+	if code := run(append(args, "-force")); code != exitOK || requests.Load() != 2 {
+		t.Errorf("-force: exit %d after %d requests, want both years fetched", code, requests.Load())
+	}
+}
+
+func TestRunConfigPrecedence(t *testing.T) {
+	srv := newSite(t, nil, 2017, 2018)
+	cfgOut, cliOut := t.TempDir(), t.TempDir()
+	config := filepath.Join(t.TempDir(), "config.json")
+	body := fmt.Sprintf(`{"year": 2017, "out": %q, "base_url": %q, "rps": 0}`, cfgOut, srv.URL+"/judgments/")
+	if err := os.WriteFile(config, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := run([]string{"-config", config, "-quiet"}); code != exitOK {
+		t.Fatalf("run with the config alone exited %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(cfgOut, "sci_judgments_2017.json")); err != nil {
+		t.Errorf("the configured year was not written to the configured directory: %v", err)
+	}
+
+	if code := run([]string{"-config", config, "-quiet", "-out", cliOut, "-from", "2018", "-to", "2018"}); code != exitOK {
+		t.Fatalf("run with overrides exited %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(cliOut, "sci_judgments_2018.json")); err != nil {
+		t.Errorf("the command-line year and directory were not used: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cliOut, "sci_judgments_2017.json")); !os.IsNotExist(err) {
+		t.Errorf("the configured year was scraped despite -from/-to: %v", err)
+	}
+
+	if err := os.WriteFile(config, []byte(`{"year": 1900}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-config", config, "-quiet"}); code != exitUsage {
+		t.Errorf("run with an invalid config exited %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunSummaryReport(t *testing.T) {
+	srv := newSite(t, nil, 2018)
+	out := t.TempDir()
+	var code int
+	printed := captureStdout(t, func() {
+		code = run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2018,2019", "-concurrency", "2"})
+	})
+	if code != exitPartial {
+		t.Errorf("run exited %d, want %d", code, exitPartial)
+	}
+	var y2018, y2019, total string
+	for _, line := range strings.Split(printed, "\n") {
+		switch f := strings.Fields(line); {
+		case len(f) == 0:
+		case f[0] == "2018":
+			y2018 = line
+		case f[0] == "2019":
+			y2019 = line
+		case f[0] == "total":
+			total = line
+		}
+	}
+	if !strings.Contains(y2018, " ok ") || !strings.Contains(y2018, " 1 ") {
+		t.Errorf("2018 line = %q, want ok with 1 judgment", y2018)
+	}
+	if !strings.Contains(y2019, " failed ") || !strings.Contains(y2019, "404") {
+		t.Errorf("2019 line = %q, want failed with the HTTP error", y2019)
+	}
+	if !strings.Contains(total, "1 ok, 1 failed, 0 skipped") {
+		t.Errorf("total line = %q", total)
+	}
+}
+
+func TestRunPartialPage(t *testing.T) {
+	var p2 atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/p2" {
+			p2.Add(1)
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, strings.Replace(yearPage(2018), "<html>", `<html><head><link rel="next" href="/p2"></head>`, 1))
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	var code int
+	printed := captureStdout(t, func() {
+		code = run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-year", "2018", "-page-retries", "1", "-retry-delay", "0"})
+	})
+	if code != exitPartial {
+		t.Errorf("run exited %d, want %d", code, exitPartial)
+	}
+	if n := p2.Load(); n != 2 {
+		t.Errorf("the failing page was requested %d times, want 2", n)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []json.RawMessage
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 {
+		t.Errorf("wrote %d judgments, want the first page's 1", len(js))
+	}
+	if !strings.Contains(printed, "2018  partial  1 ") || !strings.Contains(printed, "0 ok, 0 failed, 0 skipped, 1 partial") {
+		t.Errorf("summary %q does not mark 2018 partial", printed)
+	}
+	if got := readFailures(t, out); len(got) != 1 || got[0].Category != "partial" {
+		t.Errorf("failures.json = %+v, want 2018 as partial", got)
+	}
+}
+
+func TestRunBaseline(t *testing.T) {
+	srv := newSite(t, nil, 2018)
+	base := t.TempDir()
+	args := []string{"-base-url", srv.URL + "/judgments/", "-rps", "0", "-year", "2018"}
+	if code := run(append(args, "-out", base, "-quiet")); code != exitOK {
+		t.Fatalf("the baseline run exited %d", code)
+	}
+	var code int
+	printed := captureStdout(t, func() { code = run(append(args, "-out", t.TempDir(), "-baseline", base)) })
+	if code != exitOK || !strings.Contains(printed, "year 2018: 0 added, 0 removed, 0 changed") {
+		t.Errorf("an unchanged scrape exited %d and printed %q", code, printed)
+	}
+
+	// the baseline has the judgment with another summary, and one the site
+	// no longer lists
+	path := filepath.Join(base, "sci_judgments_2018.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []map[string]any
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	gone := maps.Clone(js[0])
+	gone["pdf_link"] = srv.URL + "/gone.pdf"
+	js[0]["judgment_summary"] = "edited"
+	if data, err = json.Marshal(append(js, gone)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name  string
+		extra []string
+		want  int
+	}{
+		{"default thresholds", nil, exitDrift},
+		{"within thresholds", []string{"-max-removed", "1", "-max-changed", "-1"}, exitOK},
+		{"over one threshold", []string{"-max-removed", "1"}, exitDrift},
+		// the baseline is read before the year's file is replaced
+		{"baseline is the output", []string{"-out", base}, exitDrift},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := append(append(slices.Clone(args), "-out", t.TempDir(), "-quiet", "-baseline", base), tc.extra...)
+			var code int
+			printed := captureStdout(t, func() { code = run(a) })
+			if code != tc.want {
+				t.Errorf("run exited %d, want %d", code, tc.want)
+			}
+			// -quiet prints the diff only when it fails the run
+			if want := tc.want == exitDrift; strings.Contains(printed, "baseline: 0 added, 1 removed, 1 changed") != want {
+				t.Errorf("printed %q, want the diff: %v", printed, want)
+			}
+		})
+	}
+	if data, err := os.ReadFile(path); err != nil || strings.Contains(string(data), "gone.pdf") {
+		t.Errorf("the run writing to -baseline left it as it was: %v", err)
+	}
+}
+
+// subjectSite serves each year's page with one judgment per subject given
+// for it, linked to /<year>-<n>.pdf.
+func subjectSite(t *testing.T, subjects map[string][]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		y := r.URL.Query().Get("judgment_year")
+		list, ok := subjects[y]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `<html><body><table><tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>`)
+		for i, subject := range list {
+			fmt.Fprintf(w, `<tr><td>%d</td><td>12-03-%s</td><td>P%d vs State</td><td>%s</td><td>s</td><td><a href="/%s-%d.pdf">PDF</a></td></tr>`, i+1, y, i, subject, y, i)
+		}
+		io.WriteString(w, `</table></body></html>`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRunSubjects(t *testing.T) {
+	srv := subjectSite(t, map[string][]string{
+		"2017": {"Tax", "Service Law", ""},
+		"2018": {"tax", "Arbitration", "Service  Law"},
+	})
+	out := t.TempDir()
+	args := []string{"-base-url", srv.URL + "/judgments/", "-rps", "0", "-quiet", "-years", "2017,2018", "-subjects"}
+	if code := run(append(args, "-out", out)); code != exitOK {
+		t.Fatalf("run exited %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "subjects.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Arbitration", "Service Law", "Tax"}; !slices.Equal(got, want) {
+		t.Errorf("subjects.json = %q, want %q", got, want)
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("-subjects wrote %d files, want only subjects.json", len(entries))
+	}
+
+	var code int
+	printed := captureStdout(t, func() { code = run(append(args, "-out", "-")) })
+	if code != exitOK || !strings.Contains(printed, `"Arbitration"`) || strings.Contains(printed, "judgment_date") {
+		t.Errorf("-subjects -out - exited %d and printed %q, want only the subjects", code, printed)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "new", "out")
+	if err := checkWritable(dir); err != nil {
+		t.Fatalf("a missing directory: %v", err)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("the probe left %d files behind: %v", len(entries), err)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWritable(filepath.Join(file, "out")); err == nil {
+		t.Error("a directory under a regular file passed")
+	}
+	if err := checkWritable(file); err == nil {
+		t.Error("a regular file passed as the directory")
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root writes to read-only directories")
+		}
+		ro := t.TempDir()
+		if err := os.Chmod(ro, 0o555); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(ro, 0o755) })
+		if err := checkWritable(ro); err == nil {
+			t.Error("a read-only directory passed")
+		}
+	})
+}
+
+func TestRunUnwritableOut(t *testing.T) {
+	var requests atomic.Int32
+	srv := newSite(t, func(*http.Request) { requests.Add(1) }, 2018)
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", filepath.Join(file, "out"), "-rps", "0", "-quiet", "-years", "2017,2018"}); code != exitError {
+		t.Errorf("run exited %d, want %d", code, exitError)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("made %d requests before failing on the output directory", n)
+	}
+}
+
+func TestRunSerialInterval(t *testing.T) {
+	var mu sync.Mutex
+	var arrived []time.Time
+	var inFlight, peak int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrived = append(arrived, time.Now())
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		// a slow response, so that a second request would overlap it
+		time.Sleep(10 * time.Millisecond)
+		if strings.HasSuffix(r.URL.Path, ".pdf") {
+			io.WriteString(w, "%PDF-1.4")
+			return
+		}
+		y, _ := strconv.Atoi(r.URL.Query().Get("judgment_year"))
+		io.WriteString(w, yearPage(y))
+	}))
+	defer srv.Close()
+	const interval = 40 * time.Millisecond
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", t.TempDir(), "-quiet", "-years", "2016-2018", "-download-pdfs",
+		"-serial-interval", fmt.Sprint(interval.Milliseconds()), "-concurrency", "3", "-pdf-concurrency", "3", "-rps", "1000"}
+	if code := run(args); code != exitOK {
+		t.Fatalf("run exited %d", code)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(arrived) != 6 {
+		t.Fatalf("made %d requests, want a page and a PDF for each of 3 years", len(arrived))
+	}
+	if peak != 1 {
+		t.Errorf("%d requests were in flight at once, want 1", peak)
+	}
+	for i := 1; i < len(arrived); i++ {
+		// the limiter's clock and the server's differ by scheduling jitter
+		if gap := arrived[i].Sub(arrived[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("request %d came %v after the one before, want at least %v", i, gap, interval)
+		}
+	}
+}
+
+func TestRunPDFList(t *testing.T) {
+	var pdfRequests atomic.Int32
+	srv := newSite(t, func(r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".pdf") {
+			pdfRequests.Add(1)
+		}
+	}, 2017, 2018)
+	out := t.TempDir()
+	want := []string{srv.URL + "/2017.pdf", srv.URL + "/2018.pdf"}
+	for _, name := range []string{"links.txt", "links.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2017-2019", "-pdf-list-file", path}); code != exitPartial {
+			t.Fatalf("run exited %d, want %d for the missing 2019", code, exitPartial)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		if name == "links.json" {
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			got = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s lists %q, want %q", name, got, want)
+		}
+	}
+	if n := pdfRequests.Load(); n != 0 {
+		t.Errorf("made %d PDF requests, want none", n)
+	}
+	// the years are still written as usual
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2018.json")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	srv := newSite(t, nil, 2017, 2018)
+	out := filepath.Join(t.TempDir(), "out")
+	for _, filters := range [][]string{
+		nil,
+		{"-years", "2018", "-subject", "Tax", "-from-date", "2018-01-01"},
+	} {
+		var code int
+		printed := captureStdout(t, func() {
+			args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-years", "2017,2018", "-dry-run", "-merge", "-hash"}
+			code = run(append(args, filters...))
+		})
+		if code != exitOK {
+			t.Errorf("filters %q: dry run exited %d", filters, code)
+		}
+		if !strings.Contains(printed, "year 2018: 1 judgments (dry run") {
+			t.Errorf("filters %q: dry run printed %q, want the count of 2018", filters, printed)
+		}
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("dry run created the output directory: %v", err)
+	}
+}
+
+func TestRunEnvPrecedence(t *testing.T) {
+	srv := newSite(t, nil, 2017, 2018)
+	envOut, cfgOut, cliOut := t.TempDir(), t.TempDir(), t.TempDir()
+	config := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(config, []byte(fmt.Sprintf(`{"out": %q, "year": 2018}`, cfgOut)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SCI_OUT", envOut)
+	t.Setenv("SCI_YEARS", "2017")
+	base := []string{"-base-url", srv.URL + "/judgments/", "-rps", "0", "-quiet", "-config", config}
+
+	if code := run(base); code != exitOK {
+		t.Fatalf("run exited %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(envOut, "sci_judgments_2017.json")); err != nil {
+		t.Errorf("the environment's year and directory did not win over the config file: %v", err)
+	}
+	if entries, _ := os.ReadDir(cfgOut); len(entries) > 0 {
+		t.Errorf("the config file's directory was written to")
+	}
+
+	if code := run(append(base, "-out", cliOut, "-years", "2018")); code != exitOK {
+		t.Fatalf("run with flags exited %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(cliOut, "sci_judgments_2018.json")); err != nil {
+		t.Errorf("the flags did not win over the environment: %v", err)
+	}
+
+	t.Setenv("SCI_CONCURRENCY", "many")
+	if code := run(base); code != exitUsage {
+		t.Errorf("a malformed SCI_CONCURRENCY exited %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := newSite(t, func(r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Query().Get("judgment_year"))
+		mu.Unlock()
+	}, 2016, 2017, 2018)
+	out := t.TempDir()
+	state := filepath.Join(t.TempDir(), "resume.json")
+	// an earlier run completed 2017 before it died; its file has since been
+	// deleted
+	if err := writeResume(state, resumeState{Completed: []int{2017}}); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2016-2019", "-resume", state}
+	if code := run(args); code != exitPartial {
+		t.Errorf("run exited %d, want %d for the missing 2019", code, exitPartial)
+	}
+	slices.Sort(requested)
+	if want := []string{"2016", "2018", "2019"}; !slices.Equal(requested, want) {
+		t.Errorf("requested years %v, want %v", requested, want)
+	}
+	st, err := readResume(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2016, 2017, 2018}; !slices.Equal(st.Completed, want) {
+		t.Errorf("resume file holds %v, want %v", st.Completed, want)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2017.json")); !os.IsNotExist(err) {
+		t.Errorf("the completed year was scraped again: %v", err)
+	}
+
+	requested = nil
+	run(append(args, "-years", "2016-2018"))
+	if len(requested) != 0 {
+		t.Errorf("a completed run requested %v again", requested)
+	}
+	run(append(args, "-years", "2016-2018", "-force"))
+	if len(requested) != 3 {
+		t.Errorf("-force requested %v, want every year", requested)
+	}
+}
+
+func TestRunParseFile(t *testing.T) {
+	saved := filepath.Join(t.TempDir(), "2018.html")
+	if err := os.WriteFile(saved, []byte(yearPage(2018)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	args := []string{"-parse-file", saved, "-base-url", "https://example.org/judgments/", "-out", out, "-quiet"}
+	if code := run(append(args, "-year", "2018")); code != exitOK {
+		t.Fatalf("run exited %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []struct {
+		Date    string `json:"judgment_date"`
+		PDFLink string `json:"pdf_link"`
+	}
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 || js[0].Date != "12-03-2018" || js[0].PDFLink != "https://example.org/2018.pdf" {
+		t.Errorf("parsed %+v, want the saved judgment with its link resolved against -base-url", js)
+	}
+
+	dry := t.TempDir()
+	printed := captureStdout(t, func() {
+		run([]string{"-parse-file", saved, "-year", "2018", "-out", dry, "-dry-run"})
+	})
+	if !strings.Contains(printed, "year 2018: 1 judgments (dry run") {
+		t.Errorf("-dry-run printed %q, want the judgment count", printed)
+	}
+	if entries, _ := os.ReadDir(dry); len(entries) > 0 {
+		t.Errorf("-dry-run wrote %d files", len(entries))
+	}
+
+	if code := run(args); code != exitUsage {
+		t.Errorf("-parse-file without -year exited %d, want %d", code, exitUsage)
+	}
+	if code := run(append(args, "-year", "2018", "-parse-file", filepath.Join(out, "missing.html"))); code != exitAllFailed {
+		t.Errorf("a missing saved file exited %d, want %d", code, exitAllFailed)
+	}
+}
+
+func TestParseYears(t *testing.T) {
+	for spec, want := range map[string][]int{
+		"2018":                {2018},
+		"2017,2019,2023":      {2017, 2019, 2023},
+		"2023, 2017-2019":     {2017, 2018, 2019, 2023},
+		"2018,2017-2018,2018": {2017, 2018},
+		"2019 - 2020":         {2019, 2020},
+	} {
+		got, err := parseYears(spec, 2016, 2025)
+		if err != nil || !slices.Equal(got, want) {
+			t.Errorf("parseYears(%q) = %v, %v; want %v", spec, got, err, want)
+		}
+	}
+	for spec, token := range map[string]string{
+		"2017,abc":       `"abc"`,
+		"2017,,2018":     `""`,
+		"2019-2017":      `"2019-2017"`,
+		"2017-":          `"2017-"`,
+		"2015,2017":      `"2015" is outside the supported range 2016..2025`,
+		"2024-2026":      `"2024-2026" is outside`,
+		"2017;2018":      `"2017;2018"`,
+		"2017,2018-20x9": `"2018-20x9"`,
+	} {
+		if _, err := parseYears(spec, 2016, 2025); err == nil || !strings.Contains(err.Error(), token) {
+			t.Errorf("parseYears(%q) error = %v, want one naming %s", spec, err, token)
+		}
+	}
+}
+
+func TestRunYearsOverridesRange(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := newSite(t, func(r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Query().Get("judgment_year"))
+		mu.Unlock()
+	}, 2017, 2019)
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", t.TempDir(), "-rps", "0", "-quiet", "-from", "2016", "-to", "2020", "-years", "2019,2017"}
+	if code := run(args); code != exitOK {
+		t.Errorf("run exited %d", code)
+	}
+	slices.Sort(requested)
+	if want := []string{"2017", "2019"}; !slices.Equal(requested, want) {
+		t.Errorf("requested %v, want only the listed years %v", requested, want)
+	}
+	if code := run([]string{"-years", "2017,20l8", "-quiet"}); code != exitUsage {
+		t.Errorf("a malformed -years exited %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunDBPath(t *testing.T) {
+	srv := newSite(t, nil, 2017, 2018)
+	out := t.TempDir()
+	db := filepath.Join(t.TempDir(), "judgments.db")
+	if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2017,2018", "-format", "sqlite", "-db", db}); code != exitOK {
+		t.Fatalf("run exited %d, want %d", code, exitOK)
+	}
+	if info, err := os.Stat(db); err != nil || info.Size() == 0 {
+		t.Errorf("-db file not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments.db")); !os.IsNotExist(err) {
+		t.Errorf("the default database was written too: %v", err)
+	}
+}
+
+func TestRunMinDelay(t *testing.T) {
+	const delay = 60 * time.Millisecond
+	for _, rps := range []string{"0", "100"} {
+		var mu sync.Mutex
+		var arrived []time.Time
+		srv := newSite(t, func(*http.Request) {
+			mu.Lock()
+			arrived = append(arrived, time.Now())
+			mu.Unlock()
+		}, 2016, 2017, 2018, 2019)
+		args := []string{"-base-url", srv.URL + "/judgments/", "-out", t.TempDir(), "-rps", rps, "-quiet", "-years", "2016-2019", "-concurrency", "4", "-min-delay", delay.String()}
+		if code := run(args); code != exitOK {
+			t.Fatalf("-rps %s: run exited %d, want %d", rps, code, exitOK)
+		}
+		mu.Lock()
+		// a single gap can shrink as requests race to the server, so check
+		// the span of all of them
+		span := arrived[len(arrived)-1].Sub(arrived[0])
+		mu.Unlock()
+		if want := time.Duration(len(arrived)-1) * delay; span < want-20*time.Millisecond {
+			t.Errorf("-rps %s: %d requests within %v, want at least %v", rps, len(arrived), span, want)
+		}
+	}
+}
+
+func TestRunIncremental(t *testing.T) {
+	var mu sync.Mutex
+	rows := []string{`<tr><td>1</td><td>12-03-2018</td><td>A vs B</td><td>Tax</td><td>s</td><td><a href="/a.pdf">PDF</a></td></tr>`}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `<html><body><table><tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>%s</table></body></html>`, strings.Join(rows, ""))
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	state := filepath.Join(t.TempDir(), "seen.json")
+	written := func(extra ...string) []string {
+		t.Helper()
+		args := append([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2018", "-incremental", state}, extra...)
+		if code := run(args); code != exitOK {
+			t.Fatalf("run(%q) exited %d", extra, code)
+		}
+		data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var js []struct {
+			PDFLink string `json:"pdf_link"`
+		}
+		if err := json.Unmarshal(data, &js); err != nil {
+			t.Fatal(err)
+		}
+		var links []string
+		for _, j := range js {
+			links = append(links, strings.TrimPrefix(j.PDFLink, srv.URL))
+		}
+		return links
+	}
+
+	if got := written(); !slices.Equal(got, []string{"/a.pdf"}) {
+		t.Errorf("first run wrote %q, want every judgment", got)
+	}
+	if got := written(); len(got) != 0 {
+		t.Errorf("a run with nothing new wrote %q, want an emptied file", got)
+	}
+	mu.Lock()
+	rows = append(rows, `<tr><td>2</td><td>13-03-2018</td><td>C vs D</td><td>Tax</td><td>s</td><td><a href="/b.pdf">PDF</a></td></tr>`)
+	mu.Unlock()
+	if got := written(); !slices.Equal(got, []string{"/b.pdf"}) {
+		t.Errorf("a run after a judgment was added wrote %q, want only the new one", got)
+	}
+	if got := written("-full"); !slices.Equal(got, []string{"/a.pdf", "/b.pdf"}) {
+		t.Errorf("-full wrote %q, want every judgment", got)
+	}
+	if got := written(); len(got) != 0 {
+		t.Errorf("a run after -full wrote %q, want nothing new", got)
+	}
+}
+
+func TestRunIncrementalRetriedDownload(t *testing.T) {
+	var pdfHits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ".pdf") {
+			io.WriteString(w, yearPage(2018))
+			return
+		}
+		// the first download fails with a retryable error
+		if pdfHits.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, "%PDF-1.4 fixture")
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	state := filepath.Join(t.TempDir(), "seen.json")
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2018", "-incremental", state, "-download-pdfs", "-retries", "1", "-retry-delay", "0"}
+	if code := run(args); code != exitOK {
+		t.Fatalf("run exited %d, want %d", code, exitOK)
+	}
+	if n := pdfHits.Load(); n != 2 {
+		t.Errorf("the PDF was requested %d times, want a failure and a retry", n)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []struct {
+		PDFPath string `json:"pdf_path"`
+	}
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 || js[0].PDFPath == "" {
+		t.Fatalf("the retried year wrote %s, want its judgment with the downloaded PDF", data)
+	}
+	if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(js[0].PDFPath))); err != nil {
+		t.Errorf("the PDF was not saved: %v", err)
+	}
+	// the judgment is recorded as seen once the year succeeded
+	run(args)
+	if data, _ := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json")); strings.TrimSpace(string(data)) != "[]" {
+		t.Errorf("a later run wrote %s, want nothing new", data)
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	for _, tc := range []struct {
+		name, in string
+		from, to string
+		ok       bool
+	}{
+		{"range", "2019-06-01,2021-03-31", "2019-06-01", "2021-03-31", true},
+		{"spaces", " 2019-06-01 , 2019-06-30 ", "2019-06-01", "2019-06-30", true},
+		{"single day", "2019-06-01,2019-06-01", "2019-06-01", "2019-06-01", true},
+		{"reversed", "2021-03-31,2019-06-01", "", "", false},
+		{"single bound", "2019-06-01", "", "", false},
+		{"open end", "2019-06-01,", "", "", false},
+		{"open start", ",2019-06-01", "", "", false},
+		{"not dates", "june,july", "", "", false},
+		{"day first", "01-06-2019,31-03-2021", "", "", false},
+		{"no such day", "2019-02-30,2019-03-31", "", "", false},
+		{"three bounds", "2019-01-01,2019-06-01,2019-12-31", "", "", false},
+		{"empty", "", "", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := parseDateRange(tc.in)
+			if !tc.ok {
+				if err == nil {
+					t.Errorf("parseDateRange(%q) = %v, %v, want an error", tc.in, from, to)
+				}
+				return
+			}
+			if err != nil || !from.Equal(day(tc.from)) || !to.Equal(day(tc.to)) {
+				t.Errorf("parseDateRange(%q) = %v, %v, %v, want %s to %s", tc.in, from, to, err, tc.from, tc.to)
+			}
+		})
+	}
+}
+
+func TestRunDateRange(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := newSite(t, func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested = append(requested, r.URL.Query().Get("judgment_year"))
+	}, 2016, 2017, 2018, 2019)
+	out := t.TempDir()
+	// each year's one judgment is dated 12 March, so 2017's is out of range
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-date-range", "2017-06-01,2018-03-31"}
+	if code := run(args); code != exitOK {
+		t.Fatalf("run exited %d, want %d with a year that has nothing in range", code, exitOK)
+	}
+	slices.Sort(requested)
+	if want := []string{"2017", "2018"}; !slices.Equal(requested, want) {
+		t.Errorf("requested years %q, want %q", requested, want)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2017.json")); !os.IsNotExist(err) {
+		t.Errorf("the year with nothing in range was written: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "12-03-2018") {
+		t.Errorf("2018 file %s lacks its judgment in range", data)
+	}
+	if _, err := os.Stat(filepath.Join(out, "failures.json")); !os.IsNotExist(err) {
+		t.Errorf("a year with nothing in range was reported failed: %v", err)
+	}
+}
+
+func TestRunDateRangeWithLimit(t *testing.T) {
+	var rows strings.Builder
+	for i, date := range []string{"10-01-2018", "10-02-2018", "10-03-2018", "10-07-2018", "10-08-2018", "10-09-2018", "10-10-2018"} {
+		fmt.Fprintf(&rows, `<tr><td>%d</td><td>%s</td><td>P%d vs State</td><td>Tax</td><td>s</td><td><a href="/%d.pdf">PDF</a></td></tr>`, i+1, date, i, i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><body><table><tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>%s</table></body></html>`, rows.String())
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-date-range", "2018-07-01,2018-12-31", "-limit", "2"}
+	if code := run(args); code != exitOK {
+		t.Fatalf("run exited %d, want %d", code, exitOK)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []struct {
+		Date string `json:"judgment_date"`
+	}
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 2 || js[0].Date != "10-07-2018" || js[1].Date != "10-08-2018" {
+		t.Errorf("wrote %+v, want the first 2 judgments in range", js)
+	}
+}
+
+func TestRunLatest(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := newSite(t, func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested = append(requested, r.URL.Query().Get("judgment_year"))
+	}, 2017, 2018)
+	out := t.TempDir()
+	earlier := `[{"judgment_date":"01-01-2018","cause_title_case_no":"Earlier vs State","subject":"Tax","judgment_summary":"s","pdf_link":"https://example.org/earlier.pdf"}]`
+	if err := os.WriteFile(filepath.Join(out, "sci_judgments_2018.json"), []byte(earlier), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-latest", "-max-year", "2018"}); code != exitOK {
+		t.Fatalf("run exited %d, want %d", code, exitOK)
+	}
+	if !slices.Equal(requested, []string{"2018"}) {
+		t.Errorf("requested years %q, want only the latest", requested)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js []struct {
+		PDFLink string `json:"pdf_link"`
+	}
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://example.org/earlier.pdf", srv.URL + "/2018.pdf"}; len(js) != 2 || js[0].PDFLink != want[0] || js[1].PDFLink != want[1] {
+		t.Errorf("the year file holds %+v, want the earlier judgment and the new one", js)
+	}
+}
+
+func TestRunEmptyYear(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<html><body><table><tr><th>S.No</th><th>Cause Title</th></tr><tr><td colspan="2">No records found</td></tr></table></body></html>`)
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2018"}); code != exitOK {
+		t.Errorf("a legitimately empty year exited %d, want %d", code, exitOK)
+	}
+	if entries, _ := os.ReadDir(out); len(entries) > 0 {
+		t.Errorf("an empty year wrote %d files", len(entries))
+	}
+}
+
+func TestRunChecksumVerify(t *testing.T) {
+	srv := newSite(t, nil, 2017, 2018)
+	out := t.TempDir()
+	if code := run([]string{"-base-url", srv.URL + "/judgments/", "-out", out, "-rps", "0", "-quiet", "-years", "2017,2018", "-checksum"}); code != exitOK {
+		t.Fatalf("scrape exited %d", code)
+	}
+	verify := []string{"-out", out, "-verify", "-quiet"}
+	if code := run(verify); code != exitOK {
+		t.Errorf("-verify of fresh files exited %d", code)
+	}
+	path := filepath.Join(out, "sci_judgments_2018.json")
+	if err := os.WriteFile(path, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run(verify); code != exitError {
+		t.Errorf("-verify of a corrupted file exited %d, want %d", code, exitError)
+	}
+}
+
+func TestRunHeaderAndCookieFlags(t *testing.T) {
+	var mu sync.Mutex
+	var token, agent, user string
+	srv := newSite(t, func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		token, agent = r.Header.Get("X-Token"), r.Header.Get("User-Agent")
+		if c, err := r.Cookie("user"); err == nil {
+			user = c.Value
+		}
+	}, 2018)
+	args := []string{"-base-url", srv.URL + "/judgments/", "-out", t.TempDir(), "-rps", "0", "-quiet", "-years", "2018", "-user-agent", "bot/1"}
+	if code := run(append(args, "-header", "x-token = t0", "-cookie", "user=u1")); code != exitOK {
+		t.Fatalf("run exited %d", code)
+	}
+	if token != "t0" || user != "u1" || agent != "bot/1" {
+		t.Errorf("server saw X-Token %q, cookie user %q, User-Agent %q; want t0, u1, bot/1", token, user, agent)
+	}
+	if code := run(append(args, "-header", "User-Agent=other/2")); code != exitOK || agent != "other/2" {
+		t.Errorf("a User-Agent header: exit %d, server saw %q, want it to override -user-agent", code, agent)
+	}
+	for _, bad := range [][]string{{"-header", "no-value"}, {"-header", "=v"}, {"-cookie", "a b"}} {
+		if code := run(append(args, bad...)); code != exitUsage {
+			t.Errorf("%q exited %d, want %d", bad, code, exitUsage)
+		}
+	}
+}
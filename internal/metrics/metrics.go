@@ -0,0 +1,75 @@
+// Package metrics implements scraper.Metrics with Prometheus collectors.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Prometheus records scraper metrics in Prometheus collectors.
+type Prometheus struct {
+	requests    *prometheus.CounterVec
+	parsed      *prometheus.GaugeVec
+	written     prometheus.Counter
+	retries     prometheus.Counter
+	failures    prometheus.Counter
+	scrapeTimes prometheus.Histogram
+}
+
+// New creates the collectors and registers them with reg.
+func New(reg prometheus.Registerer) *Prometheus {
+	m := &Prometheus{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sci_requests_total",
+			Help: "HTTP requests made, by response status (0 for failed requests).",
+		}, []string{"status"}),
+		parsed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "sci_judgments_parsed",
+			Help: "Judgments parsed from the latest scrape of each year.",
+		}, []string{"year"}),
+		written: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sci_judgments_written_total",
+			Help: "Judgments persisted.",
+		}),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sci_retries_total",
+			Help: "Years attempted again after a failure.",
+		}),
+		failures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sci_scrape_failures_total",
+			Help: "Year scrapes that failed.",
+		}),
+		scrapeTimes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "sci_scrape_duration_seconds",
+			Help:    "Time taken to scrape and persist a year.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+		}),
+	}
+	reg.MustRegister(m.requests, m.parsed, m.written, m.retries, m.failures, m.scrapeTimes)
+	return m
+}
+
+func (m *Prometheus) Request(status int) {
+	m.requests.WithLabelValues(strconv.Itoa(status)).Inc()
+}
+
+func (m *Prometheus) Parsed(year, judgments int) {
+	m.parsed.WithLabelValues(strconv.Itoa(year)).Set(float64(judgments))
+}
+
+func (m *Prometheus) Written(year, judgments int) {
+	m.written.Add(float64(judgments))
+}
+
+func (m *Prometheus) Retry(year int) {
+	m.retries.Inc()
+}
+
+func (m *Prometheus) ScrapeDone(year int, took time.Duration, err error) {
+	m.scrapeTimes.Observe(took.Seconds())
+	if err != nil {
+		m.failures.Inc()
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/local/sci-scraper/internal/scraper"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const page = `<html><body><table>
+<tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>
+<tr><td>1</td><td>12-03-2018</td><td>A vs B</td><td>Tax</td><td>s1</td><td><a href="/a.pdf">PDF</a></td></tr>
+<tr><td>2</td><td>13-03-2018</td><td>C vs D</td><td>Tax</td><td>s2</td><td><a href="/b.pdf">PDF</a></td></tr>
+</table></body></html>`
+
+func TestCountersAdvance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("judgment_year") != "2018" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, page)
+	}))
+	defer srv.Close()
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+	sc := &scraper.Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: log.New(io.Discard, "", 0), Metrics: m, Retries: 1}
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), []int{2018, 2019}, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[2018] != nil || results[2019] == nil {
+		t.Fatalf("results = %v, want 2018 scraped and 2019 failed", results)
+	}
+
+	for _, c := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"requests with status 200", testutil.ToFloat64(m.requests.WithLabelValues("200")), 1},
+		{"requests with status 503", testutil.ToFloat64(m.requests.WithLabelValues("503")), 2},
+		{"judgments parsed for 2018", testutil.ToFloat64(m.parsed.WithLabelValues("2018")), 2},
+		{"judgments written", testutil.ToFloat64(m.written), 2},
+		{"retries", testutil.ToFloat64(m.retries), 1},
+		{"failed scrapes", testutil.ToFloat64(m.failures), 2},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	observed := false
+	for _, f := range families {
+		if f.GetName() == "sci_scrape_duration_seconds" {
+			observed = true
+			// one observation per attempt: 2018 once, 2019 twice
+			if n := f.GetMetric()[0].GetHistogram().GetSampleCount(); n != 3 {
+				t.Errorf("scrape durations observed %d times, want 3", n)
+			}
+		}
+	}
+	if !observed {
+		t.Error("the registry has no scrape duration histogram")
+	}
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+)
+
+// ReadYear reads back the judgments of year's file in outDir, as written by
+// WriteYear in the scraper's Format. CSV files only carry the core fields.
+// A missing file is reported with an error wrapping fs.ErrNotExist.
+func (sc *Scraper) ReadYear(outDir string, year int) ([]Judgment, error) {
+	name, err := sc.YearFile(year)
+	if err != nil {
+		return nil, err
+	}
+	data, err := sc.readYearFile(filepath.Join(outDir, name))
+	if err != nil {
+		return nil, err
+	}
+	var judgments []Judgment
+	switch sc.format() {
+	case FormatJSON, FormatNDJSON:
+		return decodeJudgments(name, data)
+	case FormatCSV:
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		if !slices.Equal(records[0], csvHeader) {
+			return nil, fmt.Errorf("%s: unexpected CSV header %q", name, records[0])
+		}
+		for _, r := range records[1:] {
+			j := Judgment{Year: year, DateOfJudgment: r[0], CauseTitleCaseNo: r[1], Subject: r[2], JudgmentSummary: r[3], PDFLink: r[4]}
+			j.ParsedDate, _ = parseJudgmentDate(j.DateOfJudgment)
+			judgments = append(judgments, j)
+		}
+	default:
+		return nil, fmt.Errorf("reading back %q output is not supported", sc.Format)
+	}
+	return judgments, nil
+}
+
+// AppendYear merges judgments into year's existing file in outDir instead of
+// replacing it, and reports how many of them were new. Judgments are matched
+// by the identity used by Dedup: a freshly scraped judgment replaces the
+// stored one it matches, in place, and new ones follow the stored ones. A
+// missing file is created. The union is written back atomically.
+//
+// FormatSQLite is not supported; WriteYear already upserts into the
+// database.
+func (sc *Scraper) AppendYear(outDir string, year int, judgments []Judgment) (added int, err error) {
+	if sc.format() == FormatSQLite {
+		return 0, errors.New("appending is not supported for sqlite output, which is always merged")
+	}
+	existing, err := sc.ReadYear(outDir, year)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("reading existing judgments: %w", err)
+	}
+	merged := Dedup(existing)
+	index := make(map[string]int, len(merged))
+	for i, j := range merged {
+		index[dedupKey(j)] = i
+	}
+	for _, j := range judgments {
+		key := dedupKey(j)
+		if i, ok := index[key]; ok {
+			merged[i] = j
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, j)
+		added++
+	}
+	return added, sc.WriteYear(outDir, year, merged)
+}
@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAppendYear(t *testing.T) {
+	a := Judgment{Year: 2018, DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A vs B", JudgmentSummary: "s1", PDFLink: "https://example.org/a.pdf"}
+	b := Judgment{Year: 2018, DateOfJudgment: "13-03-2018", CauseTitleCaseNo: "C vs D", JudgmentSummary: "s2", PDFLink: "https://example.org/b.pdf"}
+	c := Judgment{Year: 2018, DateOfJudgment: "14-03-2018", CauseTitleCaseNo: "E vs F", JudgmentSummary: "s3", PDFLink: "https://example.org/c.pdf"}
+	d := Judgment{Year: 2018, DateOfJudgment: "15-03-2018", CauseTitleCaseNo: "G vs H", JudgmentSummary: "s4", PDFLink: "https://example.org/d.pdf"}
+	editedB := b
+	editedB.JudgmentSummary = "s2, corrected"
+
+	for _, f := range []Format{FormatJSON, FormatNDJSON, FormatCSV} {
+		sc := &Scraper{Format: f}
+		dir := t.TempDir()
+		summaries := func() []string {
+			t.Helper()
+			js, err := sc.ReadYear(dir, 2018)
+			if err != nil {
+				t.Fatalf("%s: %v", f, err)
+			}
+			var out []string
+			for _, j := range js {
+				out = append(out, j.JudgmentSummary)
+			}
+			return out
+		}
+		for _, step := range []struct {
+			name  string
+			in    []Judgment
+			added int
+			want  []string
+		}{
+			{"missing file", []Judgment{a, b}, 2, []string{"s1", "s2"}},
+			{"overlap", []Judgment{editedB, c, a}, 1, []string{"s1", "s2, corrected", "s3"}},
+			{"all new", []Judgment{d}, 1, []string{"s1", "s2, corrected", "s3", "s4"}},
+			{"nothing new", []Judgment{a, d}, 0, []string{"s1", "s2, corrected", "s3", "s4"}},
+		} {
+			added, err := sc.AppendYear(dir, 2018, step.in)
+			if err != nil {
+				t.Fatalf("%s, %s: %v", f, step.name, err)
+			}
+			if added != step.added {
+				t.Errorf("%s, %s: added %d, want %d", f, step.name, added, step.added)
+			}
+			if got := summaries(); !slices.Equal(got, step.want) {
+				t.Errorf("%s, %s: file holds %q, want %q", f, step.name, got, step.want)
+			}
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("%s: %d entries in the output directory, want only the year file", f, len(entries))
+		}
+	}
+
+	sqlite := &Scraper{Format: FormatSQLite}
+	if _, err := sqlite.AppendYear(t.TempDir(), 2018, []Judgment{a}); err == nil {
+		t.Error("appending to sqlite output succeeded")
+	}
+	corrupt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(corrupt, YearFileName(2018, FormatJSON)), []byte("[{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Scraper{}).AppendYear(corrupt, 2018, []Judgment{a}); err == nil {
+		t.Error("appending to a corrupt file succeeded")
+	}
+}
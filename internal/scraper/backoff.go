@@ -0,0 +1,28 @@
+package scraper
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// BackoffDelay returns how long to wait before retrying after the given
+// attempt (1-based). The delay starts at base, doubles per attempt and is
+// capped at max; the result is randomized to between half and all of it so
+// that concurrent retries spread out.
+func BackoffDelay(attempt int, base, max time.Duration) time.Duration {
+	if base <= 0 || max <= 0 {
+		return 0
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max || d <= 0 {
+		d = max
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
@@ -0,0 +1,41 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	const base, max = 100 * time.Millisecond, 2 * time.Second
+	for attempt := 0; attempt <= 12; attempt++ {
+		ceiling := base
+		for i := 1; i < attempt && ceiling < max; i++ {
+			ceiling *= 2
+		}
+		ceiling = min(ceiling, max)
+		for range 50 {
+			d := BackoffDelay(attempt, base, max)
+			if d < ceiling/2 || d > ceiling {
+				t.Fatalf("attempt %d: delay %v outside %v..%v", attempt, d, ceiling/2, ceiling)
+			}
+		}
+	}
+	// below the cap each attempt's floor is the ceiling of the one before,
+	// so delays grow whatever the jitter
+	for attempt := 1; attempt < 5; attempt++ {
+		for range 50 {
+			if d, next := BackoffDelay(attempt, base, max), BackoffDelay(attempt+1, base, max); next < d {
+				t.Fatalf("attempt %d waited %v, less than attempt %d's %v", attempt+1, next, attempt, d)
+			}
+		}
+	}
+	if d := BackoffDelay(1000, base, max); d > max || d < max/2 {
+		t.Errorf("a late attempt waited %v, want within the cap %v", d, max)
+	}
+	if d := BackoffDelay(3, 0, max); d != 0 {
+		t.Errorf("zero base waited %v", d)
+	}
+	if d := BackoffDelay(3, base, 0); d != 0 {
+		t.Errorf("zero cap waited %v", d)
+	}
+}
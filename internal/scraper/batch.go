@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ScrapeYears scrapes each year in turn with the default scraper. See
+// Scraper.ScrapeYears.
+func ScrapeYears(years []int, outDir string) (map[int]error, error) {
+	return defaultScraper.ScrapeYears(years, outDir)
+}
+
+// ScrapeYears scrapes each year in turn and returns every year's outcome,
+// nil for years that succeeded. The top-level error is reserved for failures
+// that affect every year, such as an output directory that cannot be created.
+func (sc *Scraper) ScrapeYears(years []int, outDir string) (map[int]error, error) {
+	if sc.PersistFunc == nil {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	results := make(map[int]error, len(years))
+	for _, y := range years {
+		results[y] = sc.ScrapeYear(y, outDir)
+	}
+	return results, nil
+}
+
+// MergeYears scrapes years with the default scraper and also writes them
+// combined. See Scraper.MergeYears.
+func MergeYears(years []int, outDir string) error {
+	return defaultScraper.MergeYears(years, outDir)
+}
+
+// MergeYears scrapes each year like ScrapeYears and then writes every
+// judgment scraped to sci_judgments_all.json in outDir, each tagged with its
+// year. Years that fail are left out of the merged file and their errors are
+// returned joined.
+func (sc *Scraper) MergeYears(years []int, outDir string) error {
+	ctx := context.Background()
+	mw := NewMergedWriter(outDir)
+	var errs []error
+	for _, y := range years {
+		judgments, err := sc.FetchYearWithContext(ctx, y)
+		if err == nil {
+			judgments, err = sc.persist(ctx, y, outDir, judgments)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := mw.Add(y, judgments); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// ScrapeYearsConcurrent scrapes years with the default scraper using a pool
+// of workers. See Scraper.ScrapeYearsConcurrent.
+func ScrapeYearsConcurrent(ctx context.Context, years []int, outDir string, workers int) (map[int]error, error) {
+	return defaultScraper.ScrapeYearsConcurrent(ctx, years, outDir, workers)
+}
+
+// YearResult is the outcome of one year scraped by ScrapeYearsConcurrent.
+type YearResult struct {
+	Year int
+	// Err is nil for a year that succeeded.
+	Err error
+	// Attempts is how many times the year was tried, zero for one that
+	// cancellation kept from starting.
+	Attempts int
+	// Duration spans every attempt and the waits between them.
+	Duration time.Duration
+}
+
+// ScrapeYearsConcurrent is like ScrapeYears but scrapes up to workers years
+// at a time, retrying retryable failures (see IsRetryable) up to Retries
+// times with BackoffDelay between attempts. Each attempt is bounded by
+// YearTimeout, and each worker pauses YearDelay between years; OnYearDone
+// hears of every year as it is settled. Requests still go through Limiter,
+// so the pool never outpaces the configured rate. Cancelling ctx aborts
+// in-flight years and skips the rest; their errors wrap ctx's error.
+func (sc *Scraper) ScrapeYearsConcurrent(ctx context.Context, years []int, outDir string, workers int) (map[int]error, error) {
+	if sc.PersistFunc == nil {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	results := make(map[int]error, len(years))
+	var mu sync.Mutex
+	done := func(res YearResult) {
+		mu.Lock()
+		results[res.Year] = res.Err
+		mu.Unlock()
+		if sc.OnYearDone != nil {
+			sc.OnYearDone(res)
+		}
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// no pause before a worker's first year
+			var delay time.Duration
+			for y := range jobs {
+				if !pause(ctx, delay) {
+					done(YearResult{Year: y, Err: fmt.Errorf("year %d: %w", y, ctx.Err())})
+					continue
+				}
+				delay = sc.YearDelay
+				done(sc.scrapeWithRetry(ctx, y, outDir))
+			}
+		}()
+	}
+	for i, y := range years {
+		select {
+		case jobs <- y:
+			continue
+		case <-ctx.Done():
+		}
+		for _, y := range years[i:] {
+			done(YearResult{Year: y, Err: fmt.Errorf("year %d: %w", y, ctx.Err())})
+		}
+		break
+	}
+	close(jobs)
+	wg.Wait()
+	return results, nil
+}
+
+// scrapeWithRetry scrapes year, retrying as described on
+// ScrapeYearsConcurrent.
+func (sc *Scraper) scrapeWithRetry(ctx context.Context, year int, outDir string) (res YearResult) {
+	res.Year = year
+	start := time.Now()
+	defer func() { res.Duration = time.Since(start) }()
+	for {
+		res.Attempts++
+		res.Err = sc.scrapeAttempt(ctx, year, outDir)
+		if res.Err == nil || res.Attempts > sc.Retries || !IsRetryable(res.Err) || ctx.Err() != nil {
+			return res
+		}
+		sc.logf("year %d: attempt %d failed, retrying: %v", year, res.Attempts, res.Err)
+		sc.metrics().Retry(year)
+		if !pause(ctx, BackoffDelay(res.Attempts, sc.RetryDelay, sc.MaxRetryDelay)) {
+			res.Err = fmt.Errorf("year %d: %w", year, ctx.Err())
+			return res
+		}
+	}
+}
+
+// scrapeAttempt makes one attempt at year, bounded by YearTimeout.
+func (sc *Scraper) scrapeAttempt(ctx context.Context, year int, outDir string) error {
+	if sc.YearTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sc.YearTimeout)
+		defer cancel()
+	}
+	return sc.ScrapeYearWithContext(ctx, year, outDir)
+}
+
+// pause waits d and reports whether ctx is still live afterwards.
+func pause(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,221 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScrapeYears(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2017: yearPage(row(1, "12-03-2017", "A vs B", "Tax", "s", "/a.pdf")),
+		2018: yearPage(),
+	}, nil)
+	out := filepath.Join(t.TempDir(), "out")
+	results, err := s.scraper().ScrapeYears([]int{2017, 2018}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[2017] != nil {
+		t.Errorf("results = %v, want 2017 to succeed", results)
+	}
+	if !errors.Is(results[2018], ErrNoJudgments) {
+		t.Errorf("2018: %v, want ErrNoJudgments", results[2018])
+	}
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2017.json")); err != nil {
+		t.Errorf("2017 was not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sci_judgments_2018.json")); !os.IsNotExist(err) {
+		t.Errorf("the failed year was written: %v", err)
+	}
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.scraper().ScrapeYears([]int{2017}, filepath.Join(blocker, "out")); err == nil {
+		t.Error("an output directory that cannot be created was not reported")
+	}
+}
+
+func TestScrapeYearsConcurrent(t *testing.T) {
+	pages := map[int]string{}
+	for y := 2016; y <= 2019; y++ {
+		pages[y] = yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))
+	}
+	s := newSite(t, pages, nil)
+	sc := s.scraper()
+	var mu sync.Mutex
+	persisted := map[int]int{}
+	done := map[int]YearResult{}
+	sc.PersistFunc = func(y int, js []Judgment) error {
+		mu.Lock()
+		defer mu.Unlock()
+		persisted[y] = len(js)
+		return nil
+	}
+	sc.OnYearDone = func(res YearResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, dup := done[res.Year]; dup {
+			t.Errorf("year %d reported twice", res.Year)
+		}
+		done[res.Year] = res
+	}
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), []int{2016, 2017, 2018, 2019, 2020}, t.TempDir(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 2016; y <= 2019; y++ {
+		if results[y] != nil || persisted[y] != 1 || done[y].Err != nil || done[y].Attempts != 1 {
+			t.Errorf("year %d: result %v, persisted %d, reported %+v", y, results[y], persisted[y], done[y])
+		}
+	}
+	var fe *FetchError
+	if !errors.As(results[2020], &fe) || fe.StatusCode != http.StatusNotFound {
+		t.Errorf("year 2020: %v, want a 404 FetchError", results[2020])
+	}
+	if done[2020].Attempts != 1 {
+		t.Errorf("a permanent failure was tried %d times", done[2020].Attempts)
+	}
+}
+
+func TestScrapeYearsConcurrentRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf")))
+	}))
+	defer srv.Close()
+	sc := &Scraper{BaseURL: srv.URL, Logger: log.New(io.Discard, "", 0), Retries: 2}
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	var got YearResult
+	sc.OnYearDone = func(res YearResult) { got = res }
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), []int{2018}, t.TempDir(), 2)
+	if err != nil || results[2018] != nil {
+		t.Fatalf("results = %v, %v", results, err)
+	}
+	if got.Attempts != 2 || got.Err != nil {
+		t.Errorf("OnYearDone got %+v, want success on the second attempt", got)
+	}
+}
+
+func TestScrapeYearsConcurrentCancelled(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))}, nil)
+	sc := s.scraper()
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	var mu sync.Mutex
+	var reported []YearResult
+	sc.OnYearDone = func(res YearResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, res)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	years := []int{2016, 2017, 2018}
+	results, err := sc.ScrapeYearsConcurrent(ctx, years, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, y := range years {
+		if !errors.Is(results[y], context.Canceled) {
+			t.Errorf("year %d: %v, want it cancelled", y, results[y])
+		}
+	}
+	if len(reported) != len(years) {
+		t.Errorf("OnYearDone heard of %d years, want %d", len(reported), len(years))
+	}
+	for _, res := range reported {
+		if res.Attempts != 0 {
+			t.Errorf("year %d was attempted %d times after cancellation", res.Year, res.Attempts)
+		}
+	}
+	if n := s.hits("/judgments/"); n != 0 {
+		t.Errorf("a cancelled run made %d requests", n)
+	}
+}
+
+func TestMergeYears(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2017: yearPage(row(1, "12-03-2017", "A vs B", "Tax", "s", "/a.pdf")),
+		2018: yearPage(
+			row(1, "12-03-2018", "C vs D", "Tax", "s", "/c.pdf"),
+			row(2, "13-03-2018", "E vs F", "Tax", "s", "/e.pdf"),
+		),
+	}, nil)
+	out := t.TempDir()
+	err := s.scraper().MergeYears([]int{2017, 2018, 2019}, out)
+	var fe *FetchError
+	if !errors.As(err, &fe) || fe.StatusCode != http.StatusNotFound {
+		t.Errorf("MergeYears = %v, want the failed year's 404", err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, MergedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged []Judgment
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, j := range merged {
+		got = append(got, fmt.Sprintf("%d %s", j.Year, j.CauseTitleCaseNo))
+	}
+	if want := []string{"2017 A vs B", "2018 C vs D", "2018 E vs F"}; !slices.Equal(got, want) {
+		t.Errorf("merged file holds %q, want %q", got, want)
+	}
+	for _, y := range []int{2017, 2018} {
+		if _, err := os.Stat(filepath.Join(out, YearFileName(y, FormatJSON))); err != nil {
+			t.Errorf("year %d file: %v", y, err)
+		}
+	}
+}
+
+func TestYearDelay(t *testing.T) {
+	const delay = 80 * time.Millisecond
+	years := []int{2016, 2017, 2018}
+	for _, workers := range []int{1, 2} {
+		s := pacedSite(t, years...)
+		sc := s.scraper()
+		sc.YearDelay = delay
+		sc.PersistFunc = func(int, []Judgment) error { return nil }
+		start := time.Now()
+		if _, err := sc.ScrapeYearsConcurrent(context.Background(), years, t.TempDir(), workers); err != nil {
+			t.Fatal(err)
+		}
+		took := time.Since(start)
+		s.mu.Lock()
+		first, last := s.arrived[0], s.arrived[len(s.arrived)-1]
+		s.mu.Unlock()
+		if wait := first.Sub(start); wait >= delay {
+			t.Errorf("%d workers: the first request waited %v", workers, wait)
+		}
+		if tail := took - last.Sub(start); tail >= delay {
+			t.Errorf("%d workers: the run went on %v after the last request", workers, tail)
+		}
+		if workers == 1 {
+			// allow for timer slack
+			if gap := s.minGap(); gap < delay-10*time.Millisecond {
+				t.Errorf("sequential years were fetched %v apart, want at least %v", gap, delay)
+			}
+		} else if took < delay {
+			t.Errorf("2 workers took %v for 3 years, want a pause before the third", took)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// benchLabel matches a leading "Bench:" or "Coram:" label.
+var benchLabel = regexp.MustCompile(`(?i)^\s*(?:bench|coram)\s*[:\-]\s*`)
+
+// benchSeparator matches what separates judges' names in a bench listing.
+var benchSeparator = regexp.MustCompile(`(?i)\s*(?:[,;&\n]|\band\b)\s*`)
+
+// judgeTitle matches the honorifics before a judge's name: "Hon'ble Mr.
+// Justice", "Hon'ble Dr. Justice", "Justice" and the like.
+var judgeTitle = regexp.MustCompile(`(?i)^(?:(?:hon['’]?ble|honourable|hon\.)\s*)?(?:(?:the\s+)?(?:chief\s+)?justice\s+|(?:mr|mrs|ms|dr)\.?\s+(?:justice\s+)?)?`)
+
+// judgeSuffix matches the "J." or "CJI" written after a judge's name, which
+// the separators leave as a part of its own.
+var judgeSuffix = regexp.MustCompile(`(?i)^(?:j|jj|cji)\.?$`)
+
+// benchHeader matches the headers of a column of judges; the word
+// boundaries keep "Date of Judgement" out.
+var benchHeader = regexp.MustCompile(`\b(?:bench|coram|judges?)\b`)
+
+// isBenchHeader reports whether a lowercased header names a column of
+// judges.
+func isBenchHeader(lower string) bool {
+	return benchHeader.MatchString(lower)
+}
+
+// parseBench splits a bench listing such as "Hon'ble Mr. Justice A.K. Sikri
+// and Hon'ble Mr. Justice Ashok Bhushan" into the judges' names, without
+// their honorifics. It returns nil for an empty listing.
+func parseBench(s string) []string {
+	s = benchLabel.ReplaceAllString(s, "")
+	var names []string
+	for _, part := range benchSeparator.Split(s, -1) {
+		name := normalizeWhitespace(judgeTitle.ReplaceAllString(strings.TrimSpace(part), ""))
+		if name != "" && !judgeSuffix.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBench(t *testing.T) {
+	for in, want := range map[string][]string{
+		"Hon'ble Mr. Justice A.K. Sikri and Hon'ble Mr. Justice Ashok Bhushan": {"A.K. Sikri", "Ashok Bhushan"},
+		"Bench: Dipak Misra, CJI; A.M. Khanwilkar, J. & D.Y. Chandrachud, J.":  {"Dipak Misra", "A.M. Khanwilkar", "D.Y. Chandrachud"},
+		"Coram - Hon'ble Dr. Justice D.Y. Chandrachud":                         {"D.Y. Chandrachud"},
+		"HON\u2019BLE THE CHIEF JUSTICE Dipak Misra\nJustice Indira Banerjee":  {"Dipak Misra", "Indira Banerjee"},
+		"":    nil,
+		"   ": nil,
+	} {
+		if got := parseBench(in); !slices.Equal(got, want) {
+			t.Errorf("parseBench(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBenchColumn(t *testing.T) {
+	page := `<html><body><table>` +
+		`<tr><th>S.No</th><th>Date of Judgement</th><th>Cause Title</th><th>Coram</th><th>Subject</th><th>Summary</th><th>View</th></tr>` +
+		`<tr><td>1</td><td>12-03-2018</td><td>A vs B</td><td>Hon'ble Mr. Justice A.K. Sikri, Hon'ble Mr. Justice Ashok Bhushan</td><td>Tax</td><td>s</td><td><a href="/a.pdf">PDF</a></td></tr>` +
+		`<tr><td>2</td><td>13-03-2018</td><td>C vs D</td><td></td><td>Tax</td><td>s</td><td><a href="/b.pdf">PDF</a></td></tr>` +
+		`</table></body></html>`
+	js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 2 {
+		t.Fatalf("parsed %d judgments, want 2", len(js))
+	}
+	if want := []string{"A.K. Sikri", "Ashok Bhushan"}; !slices.Equal(js[0].Bench, want) || js[0].DateOfJudgment != "12-03-2018" || js[0].Subject != "Tax" {
+		t.Errorf("first judgment = %+v, want bench %q and the other columns in place", js[0], want)
+	}
+	if js[1].Bench != nil {
+		t.Errorf("empty bench cell gave %q", js[1].Bench)
+	}
+
+	// without a bench column there is no bench
+	js, _, err = (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))), "https://example.org/", 2018)
+	if err != nil || len(js) != 1 || js[0].Bench != nil {
+		t.Errorf("page without a bench column: %+v, %v", js, err)
+	}
+}
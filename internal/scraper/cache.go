@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheMeta is stored as <key>.json next to the cached <key>.html, and as
+// <year>.meta.json next to a SaveHTMLDir snapshot (<year>-p<n> for later
+// pages of a year).
+type cacheMeta struct {
+	// RequestURL is the year URL the page was requested as; an entry made
+	// for another BaseURL is not reused.
+	RequestURL string `json:"request_url"`
+	// URL is where the page was finally served from, after redirects, and
+	// is the base for its relative links.
+	URL          string    `json:"url"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// cachePaths returns the files of the cache entry for pageURL, named
+// <year>-<hash> after the year and a hash of the URL so that the pages of a
+// year, and those of different BaseURLs, are kept apart.
+func (sc *Scraper) cachePaths(year int, pageURL string) (html, meta string) {
+	sum := sha256.Sum256([]byte(pageURL))
+	base := filepath.Join(sc.CacheDir, fmt.Sprintf("%d-%x", year, sum[:8]))
+	return base + ".html", base + ".json"
+}
+
+// pageFileName names the files kept for page n of year: the year for the
+// first page, <year>-p<n> for later ones.
+func pageFileName(year, n int) string {
+	if n <= 1 {
+		return fmt.Sprint(year)
+	}
+	return fmt.Sprintf("%d-p%d", year, n)
+}
+
+// readCache returns the cached page requested as pageURL for year, or nil. fresh reports whether it is younger than CacheTTL; a
+// stale page can still be revalidated with a conditional request.
+func (sc *Scraper) readCache(year int, pageURL string) (pg *page, fresh bool) {
+	htmlPath, metaPath := sc.cachePaths(year, pageURL)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, false
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.RequestURL != pageURL {
+		return nil, false
+	}
+	u, err := url.Parse(meta.URL)
+	if err != nil {
+		return nil, false
+	}
+	body, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return nil, false
+	}
+	fresh = sc.CacheTTL <= 0 || time.Since(meta.FetchedAt) <= sc.CacheTTL
+	return &page{body: body, url: u, lastModified: meta.LastModified, etag: meta.ETag, fetchedAt: meta.FetchedAt}, fresh
+}
+
+// writeCache stores pg as the cache entry for pageURL. The body is written
+// before the metadata so that a half-written entry is never used.
+func (sc *Scraper) writeCache(year int, pageURL string, pg *page) error {
+	if err := os.MkdirAll(sc.CacheDir, 0o755); err != nil {
+		return err
+	}
+	htmlPath, metaPath := sc.cachePaths(year, pageURL)
+	os.Remove(metaPath)
+	if err := writeFileAtomic(htmlPath, func(w io.Writer) error {
+		_, err := w.Write(pg.body)
+		return err
+	}); err != nil {
+		return err
+	}
+	return writeMeta(metaPath, cacheMeta{RequestURL: pageURL, URL: pg.url.String(), LastModified: pg.lastModified, ETag: pg.etag, FetchedAt: time.Now()})
+}
+
+// writeMeta writes meta to path as indented JSON.
+func writeMeta(path string, meta cacheMeta) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(meta)
+	})
+}
@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheHitAndExpiry(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "a.pdf"))}, nil)
+	sc := s.scraper()
+	sc.CacheDir = t.TempDir()
+	scrape := func() []Judgment {
+		t.Helper()
+		js, _, err := sc.fetchYear(context.Background(), 2018)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return js
+	}
+
+	first := scrape()
+	cached := scrape()
+	if n := s.hits("/judgments/"); n != 1 {
+		t.Errorf("a fresh cache entry was fetched again: %d requests", n)
+	}
+	if want := s.URL + "/judgments/a.pdf"; len(cached) != 1 || cached[0].PDFLink != want || first[0].PDFLink != want {
+		t.Errorf("PDF links = %q then %q, want %q from the network and the cache", first[0].PDFLink, cached[0].PDFLink, want)
+	}
+
+	other := s.scraper()
+	other.CacheDir, other.BaseURL = sc.CacheDir, s.URL+"/judgments/?lang=en"
+	if _, _, err := other.fetchYear(context.Background(), 2018); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.hits("/judgments/"); n != 2 {
+		t.Errorf("the entry of another URL was reused: %d requests", n)
+	}
+
+	sc.CacheTTL = time.Nanosecond
+	scrape()
+	if n := s.hits("/judgments/"); n != 3 {
+		t.Errorf("an expired entry was not fetched again: %d requests", n)
+	}
+
+	sc.CacheTTL, sc.RefreshCache = 0, true
+	scrape()
+	if n := s.hits("/judgments/"); n != 4 {
+		t.Errorf("RefreshCache used the cache: %d requests", n)
+	}
+}
+
+func TestConditionalRequest(t *testing.T) {
+	var mu sync.Mutex
+	etag, body := `"v1"`, yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))
+	var full, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+	sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: discard, CacheDir: t.TempDir(), CacheTTL: time.Nanosecond}
+	scrape := func() []Judgment {
+		t.Helper()
+		js, _, err := sc.fetchYear(context.Background(), 2018)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return js
+	}
+	responses := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return full, notModified
+	}
+
+	scrape()
+	js := scrape()
+	if full, notModified := responses(); len(js) != 1 || full != 1 || notModified != 1 {
+		t.Errorf("after a 304: %d judgments, %d full and %d not-modified responses; want 1, 1, 1", len(js), full, notModified)
+	}
+
+	mu.Lock()
+	etag, body = `"v2"`, yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s", "/c.pdf"),
+	)
+	mu.Unlock()
+	js = scrape()
+	if full, _ := responses(); len(js) != 2 || full != 2 {
+		t.Errorf("after the ETag changed: %d judgments from %d full responses; want 2, 2", len(js), full)
+	}
+
+	mu.Lock()
+	etag = ""
+	mu.Unlock()
+	js = scrape()
+	if full, notModified := responses(); len(js) != 2 || full != 3 || notModified != 1 {
+		t.Errorf("without an ETag: %d judgments, %d full and %d not-modified responses; want 2, 3, 1", len(js), full, notModified)
+	}
+}
+
+func TestCachePaginatedYear(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") + `</table></body></html>`
+	s := newSite(t, map[int]string{2018: page1}, map[string]string{
+		"/p2": yearPage(row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf")),
+	})
+	sc := s.scraper()
+	sc.CacheDir = t.TempDir()
+	for run := 1; run <= 2; run++ {
+		js, _, err := sc.fetchYear(context.Background(), 2018)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(js) != 2 || js[0].JudgmentSummary != "s1" || js[1].JudgmentSummary != "s2" {
+			t.Errorf("run %d: judgments = %+v, want both pages", run, js)
+		}
+	}
+	if a, b := s.hits("/judgments/"), s.hits("/p2"); a != 1 || b != 1 {
+		t.Errorf("pages fetched %d and %d times, want each once and then from the cache", a, b)
+	}
+	entries, _ := filepath.Glob(filepath.Join(sc.CacheDir, "2018-*.html"))
+	if len(entries) != 2 {
+		t.Errorf("cached pages %v, want one entry per page URL", entries)
+	}
+}
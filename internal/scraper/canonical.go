@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are the query parameters stripped from PDF links
+// when Scraper.TrackingParams is nil. Parameters starting with "utm_" are
+// always treated as tracking parameters.
+var DefaultTrackingParams = []string{"t", "ts", "_", "token", "session", "sessionid", "sid", "phpsessid", "jsessionid"}
+
+// canonicalizePDFLink returns link with the scraper's tracking parameters
+// removed from its query, the scheme and host lower-cased, a default port
+// dropped and the fragment removed, so that the same document gets the same
+// link on every run. The remaining parameters keep their order. Links that
+// don't parse as absolute URLs are returned unchanged.
+func (sc *Scraper) canonicalizePDFLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || !u.IsAbs() {
+		return link
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if host, port, err := net.SplitHostPort(u.Host); err == nil {
+		if u.Scheme == "http" && port == "80" || u.Scheme == "https" && port == "443" {
+			// an IPv6 literal keeps its brackets
+			if strings.Contains(host, ":") {
+				host = "[" + host + "]"
+			}
+			u.Host = host
+		}
+	}
+	u.Fragment = ""
+
+	strip := sc.TrackingParams
+	if strip == nil {
+		strip = DefaultTrackingParams
+	}
+	var kept []string
+	for _, part := range strings.Split(u.RawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		if n, err := url.QueryUnescape(name); err == nil {
+			name = n
+		}
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "utm_") || containsFold(strip, name) {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
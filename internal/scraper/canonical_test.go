@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizePDFLink(t *testing.T) {
+	sc := &Scraper{}
+	const want = "https://www.sci.gov.in/view-pdf/?diary_no=1234&type=j"
+	for _, link := range []string{
+		want,
+		"https://www.sci.gov.in/view-pdf/?diary_no=1234&t=1712345678&type=j",
+		"https://www.sci.gov.in/view-pdf/?diary_no=1234&type=j&PHPSESSID=abc&utm_source=mail",
+		"HTTPS://WWW.SCI.GOV.IN:443/view-pdf/?token=x&diary_no=1234&type=j#page=2",
+		"https://www.sci.gov.in/view-pdf/?diary_no=1234&type=j&_=1",
+	} {
+		if got := sc.canonicalizePDFLink(link); got != want {
+			t.Errorf("canonicalizePDFLink(%q) = %q, want %q", link, got, want)
+		}
+	}
+	for link, want := range map[string]string{
+		"http://example.org:80/a.pdf?t=1":   "http://example.org/a.pdf",
+		"http://example.org:8080/a.pdf?t=1": "http://example.org:8080/a.pdf",
+		"https://example.org/a.pdf?id=1":    "https://example.org/a.pdf?id=1",
+		"https://example.org/a.pdf?id=2":    "https://example.org/a.pdf?id=2",
+		"/relative/a.pdf?t=1":               "/relative/a.pdf?t=1",
+		"http://[::1]:80/a.pdf":             "http://[::1]/a.pdf",
+		"https://[2001:DB8::1]:443/a.pdf":   "https://[2001:db8::1]/a.pdf",
+		"http://[::1]:8080/a.pdf":           "http://[::1]:8080/a.pdf",
+		"http://[::1]/a.pdf":                "http://[::1]/a.pdf",
+	} {
+		if got := sc.canonicalizePDFLink(link); got != want {
+			t.Errorf("canonicalizePDFLink(%q) = %q, want %q", link, got, want)
+		}
+	}
+
+	custom := &Scraper{TrackingParams: []string{"rev"}}
+	if got := custom.canonicalizePDFLink("https://example.org/a.pdf?rev=3&t=1&utm_medium=x"); got != "https://example.org/a.pdf?t=1" {
+		t.Errorf("with TrackingParams [rev]: %q", got)
+	}
+}
+
+func TestCanonicalLinksDedup(t *testing.T) {
+	page := yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/view-pdf/?diary_no=1&t=111"),
+		row(2, "12-03-2018", "A vs B", "Tax", "s", "/view-pdf/?diary_no=1&t=222"),
+	)
+	js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://Example.org:443/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 2 || js[0].PDFLink != "https://example.org/view-pdf/?diary_no=1" || js[1].PDFLink != js[0].PDFLink {
+		t.Fatalf("links = %+v, want both canonicalized alike", js)
+	}
+	if d := Dedup(js); len(d) != 1 {
+		t.Errorf("Dedup kept %d of the rows differing only by a tracking parameter", len(d))
+	}
+}
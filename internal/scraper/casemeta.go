@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// caseTypes maps the canonical name of each recognized case type to the
+// spellings used in cause titles, full and abbreviated.
+var caseTypes = []struct {
+	name    string
+	pattern string
+}{
+	{"Civil Appeal", `civil\s+appeal|c\.\s*a\.`},
+	{"Criminal Appeal", `criminal\s+appeal|crl\.?\s*a(?:ppeal)?\.?`},
+	{"SLP", `special\s+leave\s+petition|s\.?\s*l\.?\s*p\.?`},
+	{"Writ Petition", `writ\s+petition|w\.\s*p\.|wp`},
+}
+
+// caseNumberPatterns match "<type> [(C)] No. <number> of <year>" for each
+// entry of caseTypes, in the same order. Group 1 is the whole case
+// reference, 2 the number and 3 the year.
+var caseNumberPatterns = func() []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(caseTypes))
+	for i, ct := range caseTypes {
+		res[i] = regexp.MustCompile(`(?i)(?:^|[^\pL])((?:` + ct.pattern + `)\s*(?:\(\s*(?:c|civil|crl|criminal)\.?\s*\))?\s*(?:nos?\.?\s*)?(\d+(?:\s*[-–]\s*\d+)?)\s*(?:of|/)\s*((?:19|20)\d{2}))\b`)
+	}
+	return res
+}()
+
+// emptyBrackets matches the brackets left behind once a bracketed case
+// reference is cut out of a title.
+var emptyBrackets = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+
+// parseCauseTitle splits a cause title such as "X vs Y, Civil Appeal No. 1234
+// of 2018" into the party names and the case type, number and year. When no
+// recognized case number is found, title is the input trimmed and the other
+// results are empty.
+func parseCauseTitle(s string) (title, caseType, caseNumber, caseYear string) {
+	s = strings.TrimSpace(s)
+	for i, re := range caseNumberPatterns {
+		m := re.FindStringSubmatchIndex(s)
+		if m == nil {
+			continue
+		}
+		const cut = " ,;:-–"
+		before := strings.TrimRight(s[:m[2]], cut)
+		after := strings.TrimLeft(s[m[3]:], cut+".")
+		title = emptyBrackets.ReplaceAllString(before+" "+after, "")
+		title = strings.Join(strings.Fields(title), " ")
+		number := strings.Join(strings.Fields(s[m[4]:m[5]]), "")
+		return title, caseTypes[i].name, number, s[m[6]:m[7]]
+	}
+	return s, "", "", ""
+}
@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCauseTitle(t *testing.T) {
+	for _, tc := range []struct {
+		in                                  string
+		title, caseType, caseNumber, caseYr string
+	}{
+		{"X vs Y, Civil Appeal No. 1234 of 2018", "X vs Y", "Civil Appeal", "1234", "2018"},
+		{"State of Punjab v. Ram Singh, Criminal Appeal Nos. 12-13 of 2017", "State of Punjab v. Ram Singh", "Criminal Appeal", "12-13", "2017"},
+		{"A vs B (Crl.A. No. 55 of 2019)", "A vs B", "Criminal Appeal", "55", "2019"},
+		{"SLP (C) No. 9876/2016 - M/s Alpha Ltd vs Union of India", "M/s Alpha Ltd vs Union of India", "SLP", "9876", "2016"},
+		{"Special Leave Petition (Civil) No. 42 of 2020 Rao vs Rao", "Rao vs Rao", "SLP", "42", "2020"},
+		{"Citizens Forum vs Union of India, W.P.(C) No. 7 of 2021", "Citizens Forum vs Union of India", "Writ Petition", "7", "2021"},
+		{"C.A. No. 300 of 2018: P vs Q", "P vs Q", "Civil Appeal", "300", "2018"},
+	} {
+		title, caseType, caseNumber, caseYear := parseCauseTitle(tc.in)
+		if title != tc.title || caseType != tc.caseType || caseNumber != tc.caseNumber || caseYear != tc.caseYr {
+			t.Errorf("parseCauseTitle(%q) = %q, %q, %q, %q; want %q, %q, %q, %q", tc.in, title, caseType, caseNumber, caseYear, tc.title, tc.caseType, tc.caseNumber, tc.caseYr)
+		}
+	}
+
+	for _, in := range []string{"In Re: Distribution of Essential Supplies", "  A vs B  ", "Transfer Petition No. 3 of 2018"} {
+		title, caseType, caseNumber, caseYear := parseCauseTitle(in)
+		if caseType != "" || caseNumber != "" || caseYear != "" {
+			t.Errorf("parseCauseTitle(%q) found %q, %q, %q in a title without a recognized case number", in, caseType, caseNumber, caseYear)
+		}
+		if want := strings.TrimSpace(in); title != want {
+			t.Errorf("parseCauseTitle(%q) title = %q, want %q", in, title, want)
+		}
+	}
+}
+
+func TestCaseMetadataParsed(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "X vs Y, Civil Appeal No. 1234 of 2018", "Tax", "s", "/a.pdf"),
+		row(2, "13-03-2018", "In Re: Cauvery Water", "Water", "s", "/b.pdf"),
+	)}, nil)
+	var got []Judgment
+	sc := s.scraper()
+	sc.PersistFunc = func(_ int, js []Judgment) error {
+		got = js
+		return nil
+	}
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("parsed %d judgments, want 2", len(got))
+	}
+	if j := got[0]; j.CauseTitleCaseNo != "X vs Y, Civil Appeal No. 1234 of 2018" || j.CaseType != "Civil Appeal" || j.CaseNumber != "1234" || j.CaseYear != "2018" {
+		t.Errorf("first judgment = %+v, want the combined title kept and the case fields set", j)
+	}
+	if j := got[1]; j.CaseType != "" || j.CaseNumber != "" || j.CaseYear != "" {
+		t.Errorf("second judgment = %+v, want no case fields", j)
+	}
+}
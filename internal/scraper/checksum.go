@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumSuffix is appended to a file's name to name its checksum sidecar.
+const ChecksumSuffix = ".sha256"
+
+// ChecksumFile returns the SHA-256 hex digest of the file at path, reading
+// it as a stream.
+func ChecksumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksum writes path's digest to path + ChecksumSuffix in the format
+// of sha256sum, so that `sha256sum -c` can check it too.
+func WriteChecksum(path string) error {
+	sum, err := ChecksumFile(path)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path+ChecksumSuffix, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%s  %s\n", sum, filepath.Base(path))
+		return err
+	})
+}
+
+// VerifyChecksum reports whether the file at path still matches the digest
+// in its sidecar written by WriteChecksum. A missing or malformed sidecar is
+// an error.
+func VerifyChecksum(path string) (bool, error) {
+	data, err := os.ReadFile(path + ChecksumSuffix)
+	if err != nil {
+		return false, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return false, fmt.Errorf("%s%s: not a SHA-256 checksum", path, ChecksumSuffix)
+	}
+	sum, err := ChecksumFile(path)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(fields[0], sum), nil
+}
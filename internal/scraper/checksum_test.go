@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChecksumVerify(t *testing.T) {
+	dir := t.TempDir()
+	sc := &Scraper{Checksum: true}
+	if err := sc.WriteYear(dir, 2018, syntheticJudgments(5)); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, YearFileName(2018, FormatJSON))
+	sidecar, err := os.ReadFile(path + ChecksumSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := ChecksumFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := sum + "  " + filepath.Base(path) + "\n"; string(sidecar) != want {
+		t.Errorf("sidecar = %q, want sha256sum format %q", sidecar, want)
+	}
+	if ok, err := VerifyChecksum(path); !ok || err != nil {
+		t.Errorf("VerifyChecksum of a fresh file = %t, %v", ok, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	data[len(data)/2] ^= 0x20
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyChecksum(path); ok || err != nil {
+		t.Errorf("VerifyChecksum of a corrupted file = %t, %v; want false without an error", ok, err)
+	}
+
+	if err := os.WriteFile(path+ChecksumSuffix, []byte("not-a-digest  x.json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyChecksum(path); err == nil || !strings.Contains(err.Error(), "not a SHA-256 checksum") {
+		t.Errorf("malformed sidecar: %v", err)
+	}
+	os.Remove(path + ChecksumSuffix)
+	if _, err := VerifyChecksum(path); !os.IsNotExist(err) {
+		t.Errorf("missing sidecar: %v", err)
+	}
+}
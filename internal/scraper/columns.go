@@ -0,0 +1,74 @@
+package scraper
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ColumnMap maps header text to the Judgment field its column holds, for
+// tables whose headers the built-in matching doesn't recognize. A column
+// whose header contains a key, ignoring case, is read into the key's
+// field; when several keys match, the longest wins. Fields are named by
+// their JSON names: judgment_date, cause_title_case_no, subject,
+// judgment_summary, pdf_link, bench, summary_en, summary_hi, or serial for a
+// serial-number column. Headers no key matches fall back to the built-in
+// matching.
+type ColumnMap map[string]string
+
+// columnFields maps the field names accepted in a ColumnMap to the internal
+// header keys used while parsing.
+var columnFields = map[string]string{
+	"judgment_date":       "date",
+	"cause_title_case_no": "cause",
+	"subject":             "subject",
+	"judgment_summary":    "summary",
+	"pdf_link":            "pdf",
+	"bench":               "bench",
+	"summary_en":          "summary_en",
+	"summary_hi":          "summary_hi",
+	"serial":              "serial",
+}
+
+type columnRule struct {
+	substr, key string
+}
+
+type columnRules []columnRule
+
+// Validate reports an unknown field name or an empty header in m.
+func (m ColumnMap) Validate() error {
+	_, err := m.compile()
+	return err
+}
+
+// compile validates m and orders its rules longest header text first.
+func (m ColumnMap) compile() (columnRules, error) {
+	var rules columnRules
+	for substr, field := range m {
+		key, ok := columnFields[strings.ToLower(strings.TrimSpace(field))]
+		if !ok {
+			return nil, fmt.Errorf("column map: unknown field %q for header %q", field, substr)
+		}
+		substr = strings.ToLower(strings.TrimSpace(substr))
+		if substr == "" {
+			return nil, fmt.Errorf("column map: empty header for field %q", field)
+		}
+		rules = append(rules, columnRule{substr, key})
+	}
+	slices.SortFunc(rules, func(a, b columnRule) int {
+		return cmp.Or(len(b.substr)-len(a.substr), strings.Compare(a.substr, b.substr))
+	})
+	return rules, nil
+}
+
+// match returns the header key for a lower-cased header text.
+func (rules columnRules) match(lower string) (string, bool) {
+	for _, r := range rules {
+		if strings.Contains(lower, r.substr) {
+			return r.key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnMapRoutesHeaders(t *testing.T) {
+	page := `<html><body><table>` +
+		`<tr><th>Sr.</th><th>Particulars</th><th>Decided On</th><th>Parties</th><th>Gist</th><th>Date of Upload</th><th>View</th></tr>` +
+		`<tr><td>1</td><td>Tax</td><td>12-03-2018</td><td>A vs B</td><td>the gist</td><td>01-04-2018</td><td><a href="/a.pdf">PDF</a></td></tr>` +
+		`</table></body></html>`
+	sc := &Scraper{Logger: discard, ColumnMap: ColumnMap{
+		"particulars": "subject",
+		"Decided":     "judgment_date",
+		"PARTIES":     "cause_title_case_no",
+		"gist":        "judgment_summary",
+		"sr.":         "serial",
+		// a longer key beats the built-in "date" match
+		"date of upload": "bench",
+	}}
+	js, _, err := sc.ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 {
+		t.Fatalf("parsed %d judgments, want 1", len(js))
+	}
+	j := js[0]
+	if j.Subject != "Tax" || j.DateOfJudgment != "12-03-2018" || j.CauseTitleCaseNo != "A vs B" || j.JudgmentSummary != "the gist" || j.PDFLink != "https://example.org/a.pdf" {
+		t.Errorf("judgment = %+v, want the mapped columns in their fields", j)
+	}
+	if len(j.Bench) != 1 || j.Bench[0] != "01-04-2018" {
+		t.Errorf("Bench = %q, want the column routed by the map ahead of the built-in date match", j.Bench)
+	}
+
+	for _, m := range []ColumnMap{{"Particulars": "topic"}, {" ": "subject"}} {
+		if err := m.Validate(); err == nil {
+			t.Errorf("Validate(%v) succeeded", m)
+		}
+		if _, _, err := (&Scraper{ColumnMap: m}).ParseHTML(strings.NewReader(page), "https://example.org/", 2018); err == nil {
+			t.Errorf("parsing with %v succeeded", m)
+		}
+	}
+}
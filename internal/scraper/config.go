@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config holds scrape settings kept in a file, as read by LoadConfig. Zero
+// fields are unset and leave the caller's defaults alone.
+type Config struct {
+	Year        int       `json:"year,omitempty"`
+	From        int       `json:"from,omitempty"`
+	To          int       `json:"to,omitempty"`
+	Out         string    `json:"out,omitempty"`
+	Concurrency int       `json:"concurrency,omitempty"`
+	Retries     int       `json:"retries,omitempty"`
+	RPS         *float64  `json:"rps,omitempty"` // 0 means unlimited
+	UserAgent   string    `json:"user_agent,omitempty"`
+	BaseURL     string    `json:"base_url,omitempty"`
+	Format      Format    `json:"format,omitempty"`
+	MinYear     int       `json:"min_year,omitempty"`
+	MaxYear     int       `json:"max_year,omitempty"`
+	ColumnMap   ColumnMap `json:"column_map,omitempty"`
+}
+
+// LoadConfig reads a JSON Config from path and validates it. Unknown keys
+// are rejected so that typos don't go unnoticed.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadEnv returns cfg overlaid with the SCI_OUT, SCI_CONCURRENCY,
+// SCI_USER_AGENT, SCI_RPS and SCI_YEARS environment variables, where set.
+// SCI_YEARS is a single year ("2018") or an inclusive range ("2017-2019").
+// The result is validated like LoadConfig's.
+func LoadEnv(cfg Config) (Config, error) {
+	if v, ok := os.LookupEnv("SCI_OUT"); ok {
+		cfg.Out = v
+	}
+	if v, ok := os.LookupEnv("SCI_USER_AGENT"); ok {
+		cfg.UserAgent = v
+	}
+	if v, ok := os.LookupEnv("SCI_CONCURRENCY"); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return cfg, fmt.Errorf("SCI_CONCURRENCY: %q is not an integer", v)
+		}
+		cfg.Concurrency = n
+	}
+	if v, ok := os.LookupEnv("SCI_RPS"); ok {
+		rps, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return cfg, fmt.Errorf("SCI_RPS: %q is not a number", v)
+		}
+		cfg.RPS = &rps
+	}
+	if v, ok := os.LookupEnv("SCI_YEARS"); ok {
+		from, to, isRange := strings.Cut(strings.TrimSpace(v), "-")
+		first, err1 := strconv.Atoi(strings.TrimSpace(from))
+		last, err2 := strconv.Atoi(strings.TrimSpace(to))
+		switch {
+		case err1 != nil || isRange && err2 != nil:
+			return cfg, fmt.Errorf("SCI_YEARS: %q is not a year or a range like 2017-2019", v)
+		case isRange:
+			cfg.Year, cfg.From, cfg.To = 0, first, last
+		default:
+			cfg.Year = first
+		}
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("environment: %w", err)
+	}
+	return cfg, nil
+}
+
+func (cfg Config) validate() error {
+	minYear, maxYear := yearBounds(cfg.MinYear, cfg.MaxYear)
+	if minYear > maxYear {
+		return fmt.Errorf("min_year %d is after max_year %d", minYear, maxYear)
+	}
+	for _, y := range []struct {
+		name string
+		v    int
+	}{{"year", cfg.Year}, {"from", cfg.From}, {"to", cfg.To}} {
+		if y.v != 0 && (y.v < minYear || y.v > maxYear) {
+			return fmt.Errorf("%s %d out of supported range %d..%d", y.name, y.v, minYear, maxYear)
+		}
+	}
+	if cfg.From != 0 && cfg.To != 0 && cfg.From > cfg.To {
+		return fmt.Errorf("from %d is after to %d", cfg.From, cfg.To)
+	}
+	if cfg.Concurrency < 0 {
+		return fmt.Errorf("concurrency %d must be positive", cfg.Concurrency)
+	}
+	if cfg.Retries < 0 {
+		return fmt.Errorf("retries %d must not be negative", cfg.Retries)
+	}
+	if cfg.RPS != nil && *cfg.RPS < 0 {
+		return errors.New("rps must not be negative")
+	}
+	if err := cfg.ColumnMap.Validate(); err != nil {
+		return err
+	}
+	switch cfg.Format {
+	case "", FormatJSON, FormatNDJSON, FormatCSV, FormatSQLite:
+	default:
+		return fmt.Errorf("unknown format %q", cfg.Format)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes body to a config file and returns its path.
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, `{"from": 2016, "to": 2018, "out": "data", "concurrency": 3, "rps": 0.5, "user_agent": "bot/1", "format": "csv"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.From != 2016 || cfg.To != 2018 || cfg.Out != "data" || cfg.Concurrency != 3 || cfg.UserAgent != "bot/1" || cfg.Format != FormatCSV {
+		t.Errorf("LoadConfig = %+v", cfg)
+	}
+	if cfg.RPS == nil || *cfg.RPS != 0.5 {
+		t.Errorf("RPS = %v, want 0.5", cfg.RPS)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{`{"year": 1949}`, "year 1949 out of supported range"},
+		{`{"from": 2019, "to": 2017}`, "from 2019 is after to 2017"},
+		{`{"concurrency": -1}`, "concurrency -1"},
+		{`{"format": "xml"}`, `unknown format "xml"`},
+		{`{"yaer": 2018}`, "unknown field"},
+	} {
+		if _, err := LoadConfig(writeConfig(t, tc.body)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("LoadConfig(%s) error = %v, want one mentioning %q", tc.body, err, tc.want)
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadConfig of a missing file succeeded")
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("SCI_OUT", "/data/sci")
+	t.Setenv("SCI_CONCURRENCY", " 4 ")
+	t.Setenv("SCI_USER_AGENT", "bot/2")
+	t.Setenv("SCI_RPS", "0.25")
+	t.Setenv("SCI_YEARS", "2017-2019")
+	cfg, err := LoadEnv(Config{Year: 2018, Out: "data", Retries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Out != "/data/sci" || cfg.Concurrency != 4 || cfg.UserAgent != "bot/2" || cfg.Year != 0 || cfg.From != 2017 || cfg.To != 2019 || cfg.Retries != 2 {
+		t.Errorf("LoadEnv = %+v", cfg)
+	}
+	if cfg.RPS == nil || *cfg.RPS != 0.25 {
+		t.Errorf("RPS = %v, want 0.25", cfg.RPS)
+	}
+
+	t.Setenv("SCI_YEARS", "2018")
+	if cfg, err := LoadEnv(Config{}); err != nil || cfg.Year != 2018 || cfg.From != 0 {
+		t.Errorf("SCI_YEARS=2018: %+v, %v", cfg, err)
+	}
+
+	for _, tc := range []struct{ name, value, want string }{
+		{"SCI_CONCURRENCY", "four", `SCI_CONCURRENCY: "four" is not an integer`},
+		{"SCI_RPS", "fast", `SCI_RPS: "fast" is not a number`},
+		{"SCI_YEARS", "2017-", "SCI_YEARS"},
+		{"SCI_YEARS", "1900", "year 1900 out of supported range"},
+		{"SCI_CONCURRENCY", "-2", "concurrency -2"},
+	} {
+		t.Run(tc.name+"="+tc.value, func(t *testing.T) {
+			t.Setenv(tc.name, tc.value)
+			if _, err := LoadEnv(Config{}); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
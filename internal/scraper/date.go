@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// judgmentDateLayouts are the date formats seen in the judgment date column.
+var judgmentDateLayouts = []string{
+	"02-01-2006",
+	"2-1-2006",
+	"02/01/2006",
+	"2/1/2006",
+	"02.01.2006",
+	"2006-01-02",
+	"02 January 2006",
+	"2 January 2006",
+	"02 Jan 2006",
+	"2 Jan 2006",
+	"02-Jan-2006",
+	"2-Jan-2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// ordinalSuffix matches day ordinals such as the "th" in "12th March 2018".
+var ordinalSuffix = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)\b`)
+
+// parseJudgmentDate parses a raw judgment date cell using the common layouts.
+func parseJudgmentDate(s string) (time.Time, bool) {
+	s = ordinalSuffix.ReplaceAllString(normalizeWhitespace(s), "$1")
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range judgmentDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// judgmentTime returns the judgment's date, parsing DateOfJudgment when
+// ParsedDate is not set.
+func judgmentTime(j Judgment) (time.Time, bool) {
+	if !j.ParsedDate.IsZero() {
+		return j.ParsedDate, true
+	}
+	return parseJudgmentDate(j.DateOfJudgment)
+}
+
+// judgmentJSON is Judgment with ParsedDate as a date-only string.
+type judgmentJSON struct {
+	plainJudgment
+	ParsedDate string `json:"parsed_date,omitempty"`
+}
+
+// plainJudgment has Judgment's fields without its JSON methods.
+type plainJudgment Judgment
+
+// MarshalJSON encodes ParsedDate as YYYY-MM-DD and omits it when zero. HTML
+// characters are not escaped, so '&' in links survives.
+func (j Judgment) MarshalJSON() ([]byte, error) {
+	aux := judgmentJSON{plainJudgment: plainJudgment(j)}
+	if !j.ParsedDate.IsZero() {
+		aux.ParsedDate = j.ParsedDate.Format(time.DateOnly)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(aux); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON.
+func (j *Judgment) UnmarshalJSON(data []byte) error {
+	var aux judgmentJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*j = Judgment(aux.plainJudgment)
+	if aux.ParsedDate != "" {
+		t, err := time.Parse(time.DateOnly, aux.ParsedDate)
+		if err != nil {
+			return err
+		}
+		j.ParsedDate = t
+	}
+	return nil
+}
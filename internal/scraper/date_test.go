@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseJudgmentDate(t *testing.T) {
+	day := time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{
+		"02-03-2018", "2-3-2018", "02/03/2018", "2/3/2018", "02.03.2018", "2018-03-02",
+		"02 March 2018", "2 March 2018", "2nd March 2018", "02 Mar 2018", "2-Mar-2018",
+		"March 2, 2018", "Mar 2, 2018", "  2\u00a0March\n2018 ",
+	} {
+		if got, ok := parseJudgmentDate(s); !ok || !got.Equal(day) {
+			t.Errorf("parseJudgmentDate(%q) = %v, %t; want %v", s, got, ok, day)
+		}
+	}
+	for _, s := range []string{"", "   ", "not a date", "31-02-2018", "2018", "12/13/2018", "March 2018"} {
+		if got, ok := parseJudgmentDate(s); ok {
+			t.Errorf("parseJudgmentDate(%q) = %v, want it rejected", s, got)
+		}
+	}
+}
+
+func TestParsedDateJSON(t *testing.T) {
+	j := Judgment{DateOfJudgment: "2nd March 2018", ParsedDate: time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC)}
+	data, err := json.Marshal(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"parsed_date":"2018-03-02"`) || !strings.Contains(string(data), `"judgment_date":"2nd March 2018"`) {
+		t.Errorf("encoded as %s", data)
+	}
+	var back Judgment
+	if err := json.Unmarshal(data, &back); err != nil || !back.ParsedDate.Equal(j.ParsedDate) {
+		t.Errorf("decoded ParsedDate %v, %v", back.ParsedDate, err)
+	}
+	if data, _ := json.Marshal(Judgment{DateOfJudgment: "garbage"}); strings.Contains(string(data), "parsed_date") {
+		t.Errorf("an unparsed date was encoded: %s", data)
+	}
+}
@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Dedup returns judgments with duplicates removed, keeping the first
+// occurrence of each and preserving order.
+//
+// Two judgments are duplicates when their PDF links are equal after
+// normalization (surrounding space trimmed, scheme and host lower-cased,
+// fragment dropped). Judgments without a PDF link are duplicates only when
+// both the judgment date and the cause title match exactly after trimming and
+// collapsing internal whitespace; letter case and punctuation are significant,
+// so near-identical titles are kept apart.
+func Dedup(judgments []Judgment) []Judgment {
+	seen := map[string]bool{}
+	var out []Judgment
+	for _, j := range judgments {
+		key := dedupKey(j)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, j)
+	}
+	return out
+}
+
+// dedupKey returns the identity key described on Dedup.
+func dedupKey(j Judgment) string {
+	if link := normalizeLink(j.PDFLink); link != "" {
+		return "pdf:" + link
+	}
+	return "title:" + normalizeWhitespace(j.DateOfJudgment) + "\x00" + normalizeWhitespace(j.CauseTitleCaseNo)
+}
+
+// normalizeLink trims a link, lower-cases its scheme and host and drops the
+// fragment. Unparseable links are only trimmed.
+func normalizeLink(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	return u.String()
+}
+
+// normalizeWhitespace collapses runs of whitespace, including newlines,
+// tabs and non-breaking spaces, into single spaces and trims the ends.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,27 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDedup(t *testing.T) {
+	in := []Judgment{
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A vs B", PDFLink: "https://Example.org/a.pdf"},
+		{DateOfJudgment: "13-03-2018", CauseTitleCaseNo: "another title", PDFLink: " https://example.org/a.pdf#page=2 "},
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "C vs D"},
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: " C  vs\tD "},
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "C vs. D"},
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "c vs d"},
+		{DateOfJudgment: "14-03-2018", CauseTitleCaseNo: "C vs D"},
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A vs B", PDFLink: "https://example.org/b.pdf"},
+	}
+	got := Dedup(in)
+	want := []Judgment{in[0], in[2], in[4], in[5], in[6], in[7]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dedup kept %+v\nwant %+v", got, want)
+	}
+	if got := Dedup(nil); len(got) != 0 {
+		t.Errorf("Dedup(nil) = %v", got)
+	}
+}
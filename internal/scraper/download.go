@@ -0,0 +1,188 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DownloadPDF downloads link into destDir with the default scraper and
+// returns the file's path. See Scraper.DownloadPDF.
+func DownloadPDF(ctx context.Context, link, destDir string) (string, error) {
+	return defaultScraper.DownloadPDF(ctx, link, destDir)
+}
+
+// DownloadPDF downloads link into destDir, naming the file after the link's
+// last path element with a short hash of the whole link, so that view-pdf
+// links differing only in their query get files of their own, and returns
+// the file's path. Redirects from view-pdf handlers are followed; the
+// response must be a PDF. An existing non-empty file is left alone.
+func (sc *Scraper) DownloadPDF(ctx context.Context, link, destDir string) (string, error) {
+	var name string
+	if u, err := url.Parse(link); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			name = strings.TrimSuffix(base, path.Ext(base))
+		}
+	}
+	dest := filepath.Join(destDir, hashedPDFName(name, link, "document"))
+	return dest, sc.downloadPDF(ctx, link, dest)
+}
+
+// DefaultPDFConcurrency is the number of PDF downloads a year runs at once
+// when Scraper.PDFConcurrency is zero.
+const DefaultPDFConcurrency = 4
+
+// downloadYearPDFs downloads the PDF of every judgment into
+// outDir/pdfs/<year>/ with PDFConcurrency workers, setting PDFPath on each
+// judgment whose PDF was saved or already on disk. Judgments whose PDFs
+// share a file, such as duplicate rows, are downloaded once.
+func (sc *Scraper) downloadYearPDFs(ctx context.Context, year int, outDir string, judgments []Judgment) error {
+	dir := path.Join("pdfs", fmt.Sprint(year))
+	workers := sc.PDFConcurrency
+	if workers <= 0 {
+		workers = DefaultPDFConcurrency
+	}
+	// the judgments saved to each file, in judgment order; a file's first
+	// judgment supplies the link
+	var names []string
+	byName := map[string][]int{}
+	for i, j := range judgments {
+		if j.PDFLink == "" {
+			continue
+		}
+		name := path.Join(dir, pdfFileName(j))
+		if byName[name] == nil {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], i)
+	}
+	var mu sync.Mutex
+	var errs []error
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each worker only touches the judgments of the files it is sent
+			for name := range jobs {
+				idx := byName[name]
+				if err := sc.downloadPDF(ctx, judgments[idx[0]].PDFLink, filepath.Join(outDir, filepath.FromSlash(name))); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					continue
+				}
+				for _, i := range idx {
+					judgments[i].PDFPath = name
+				}
+			}
+		}()
+	}
+feed:
+	for _, name := range names {
+		select {
+		case jobs <- name:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("year %d: %w", year, ctx.Err())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("downloading PDFs for %d: %w", year, err)
+	}
+	return nil
+}
+
+// downloadPDF saves link to dest unless dest already exists and is non-empty.
+func (sc *Scraper) downloadPDF(ctx context.Context, link, dest string) error {
+	if fi, err := os.Stat(dest); err == nil && fi.Size() > 0 {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	sc.setHeaders(req)
+	if err := sc.wait(ctx); err != nil {
+		return err
+	}
+	resp, err := sc.do(req)
+	if err != nil {
+		return &FetchError{URL: link, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &FetchError{StatusCode: resp.StatusCode, URL: link, Err: errors.New(resp.Status)}
+	}
+
+	// view-pdf handlers may answer with an HTML error page; accept only
+	// responses declared as PDF or starting with the PDF signature
+	body := bufio.NewReader(resp.Body)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/pdf" {
+		if magic, _ := body.Peek(5); string(magic) != "%PDF-" {
+			return fmt.Errorf("%s: not a PDF (content type %q)", link, resp.Header.Get("Content-Type"))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(dest, func(w io.Writer) error {
+		_, err := io.Copy(w, body)
+		return err
+	})
+}
+
+// pdfFileName names a judgment's PDF after its sanitized cause title, with a
+// short hash of the link so that judgments sharing a title don't collide.
+func pdfFileName(j Judgment) string {
+	return hashedPDFName(j.CauseTitleCaseNo, j.PDFLink, "judgment")
+}
+
+// hashedPDFName returns the sanitized name, or fallback when nothing of it
+// is left, followed by a short hash of link and ".pdf".
+func hashedPDFName(name, link, fallback string) string {
+	sum := sha1.Sum([]byte(link))
+	if name = sanitizeFileName(name); name == "" {
+		name = fallback
+	}
+	return name + "-" + hex.EncodeToString(sum[:4]) + ".pdf"
+}
+
+// sanitizeFileName lower-cases s and replaces runs of anything but ASCII
+// letters and digits with '-', keeping at most 80 characters.
+func sanitizeFileName(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 80 {
+			break
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
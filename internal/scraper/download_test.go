@@ -0,0 +1,189 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadYearPDFsAfterTransform(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "Rajesh vs State", "Tax", "s1", "/a.pdf"),
+		row(2, "12-03-2018", "Rajesh vs State", "Tax", "s1", "/a.pdf"),
+		row(3, "01-05-2017", "Suresh vs State", "Tax", "s2", "/b.pdf"),
+	)}, map[string]string{"/a.pdf": pdfBody, "/b.pdf": pdfBody})
+	sc := s.scraper()
+	sc.DownloadPDFs = true
+	sc.Transform = func(_ int, js []Judgment) []Judgment {
+		js = FilterDateRange(js, mustDate(t, "2018-01-01"), mustDate(t, "2018-12-31"))
+		for i := range js {
+			js[i].CauseTitleCaseNo = RedactCauseTitle(js[i].CauseTitleCaseNo, "X")
+		}
+		return js
+	}
+	var written []Judgment
+	sc.PersistFunc = func(_ int, js []Judgment) error {
+		written = js
+		return nil
+	}
+	out := t.TempDir()
+	if err := sc.ScrapeYear(2018, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := s.hits("/b.pdf"); n != 0 {
+		t.Errorf("the PDF of a row dropped by Transform was fetched %d times", n)
+	}
+	if n := s.hits("/a.pdf"); n != 1 {
+		t.Errorf("the PDF shared by duplicate rows was fetched %d times, want 1", n)
+	}
+	if len(written) != 2 {
+		t.Fatalf("persisted %d judgments, want 2", len(written))
+	}
+	for _, j := range written {
+		if strings.Contains(j.PDFPath, "rajesh") {
+			t.Errorf("PDFPath %q carries the redacted party name", j.PDFPath)
+		}
+		if j.PDFPath == "" || j.PDFPath != written[0].PDFPath {
+			t.Errorf("PDFPath = %q, want the shared file %q", j.PDFPath, written[0].PDFPath)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(written[0].PDFPath)))
+	if err != nil || string(data) != pdfBody {
+		t.Errorf("downloaded PDF = %q, %v; want %q", data, err, pdfBody)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(out, "pdfs", "2018", "*.tmp"))
+	if len(leftovers) > 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestDownloadPDFRejectsHTML(t *testing.T) {
+	s := newSite(t, nil, map[string]string{"/view-pdf/": "<html>error</html>"})
+	dest := filepath.Join(t.TempDir(), "x.pdf")
+	if err := s.scraper().downloadPDF(context.Background(), s.URL+"/view-pdf/", dest); err == nil {
+		t.Fatal("downloadPDF accepted an HTML error page")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("destination exists after a rejected download: %v", err)
+	}
+}
+
+func TestDownloadPDFNames(t *testing.T) {
+	s := newSite(t, nil, map[string]string{
+		"/docs/Judgment X (2018).pdf": pdfBody,
+		"/view-pdf/":                  pdfBody,
+	})
+	sc := s.scraper()
+	dir := t.TempDir()
+	saved := map[string]string{}
+	for _, tc := range []struct {
+		link, prefix string
+	}{
+		{s.URL + "/docs/Judgment%20X%20(2018).pdf", "judgment-x-2018-"},
+		{s.URL + "/view-pdf/?id=3", "view-pdf-"},
+		{s.URL + "/view-pdf/?id=4", "view-pdf-"},
+	} {
+		got, err := sc.DownloadPDF(context.Background(), tc.link, dir)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.link, err)
+		}
+		if name := filepath.Base(got); filepath.Dir(got) != dir || !strings.HasPrefix(name, tc.prefix) || !strings.HasSuffix(name, ".pdf") {
+			t.Errorf("%s saved as %s, want %s<hash>.pdf in %s", tc.link, got, tc.prefix, dir)
+		}
+		if other, ok := saved[got]; ok {
+			t.Errorf("%s and %s share the file %s", other, tc.link, got)
+		}
+		saved[got] = tc.link
+		if data, err := os.ReadFile(got); err != nil || string(data) != pdfBody {
+			t.Errorf("%s: file holds %q, %v", tc.link, data, err)
+		}
+	}
+	if n := s.hits("/view-pdf/"); n != 2 {
+		t.Errorf("view-pdf links with distinct ids were fetched %d times, want 2", n)
+	}
+	if _, err := sc.DownloadPDF(context.Background(), s.URL+"/view-pdf/?id=4", dir); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.hits("/view-pdf/"); n != 2 {
+		t.Errorf("an existing file was downloaded again: %d requests", n)
+	}
+
+	a := Judgment{CauseTitleCaseNo: "A vs. B & Ors.", PDFLink: "https://example.org/1.pdf"}
+	b := Judgment{CauseTitleCaseNo: "A vs. B & Ors.", PDFLink: "https://example.org/2.pdf"}
+	if na, nb := pdfFileName(a), pdfFileName(b); na == nb || !strings.HasPrefix(na, "a-vs-b-ors-") || !strings.HasSuffix(na, ".pdf") {
+		t.Errorf("pdfFileName = %q and %q, want distinct a-vs-b-ors-<hash>.pdf names", na, nb)
+	}
+	if n := pdfFileName(Judgment{PDFLink: "https://example.org/1.pdf"}); !strings.HasPrefix(n, "judgment-") {
+		t.Errorf("untitled judgment named %q", n)
+	}
+}
+
+func TestDownloadYearPDFsPool(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak, fetched := 0, 0, map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		fetched[r.URL.Path]++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, pdfBody)
+	}))
+	defer srv.Close()
+
+	var js []Judgment
+	for i := range 8 {
+		js = append(js, Judgment{CauseTitleCaseNo: fmt.Sprintf("P%d vs State", i), PDFLink: fmt.Sprintf("%s/%d.pdf", srv.URL, i)})
+	}
+	js = append(js, Judgment{CauseTitleCaseNo: "No PDF vs State"})
+	out := t.TempDir()
+	// the first judgment's file is already on disk
+	existing := filepath.Join(out, "pdfs", "2018", pdfFileName(js[0]))
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte(pdfBody), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := &Scraper{HTTPClient: srv.Client(), Logger: discard, PDFConcurrency: 3}
+	if err := sc.downloadYearPDFs(context.Background(), 2018, out, js); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if peak > 3 {
+		t.Errorf("%d downloads ran at once, want at most PDFConcurrency 3", peak)
+	}
+	if peak < 2 {
+		t.Errorf("downloads ran one at a time with PDFConcurrency 3")
+	}
+	if fetched["/0.pdf"] != 0 {
+		t.Errorf("the PDF already on disk was fetched %d times", fetched["/0.pdf"])
+	}
+	if len(fetched) != 7 {
+		t.Errorf("fetched %d PDFs, want the 7 not on disk", len(fetched))
+	}
+	for i, j := range js[:8] {
+		if want := "pdfs/2018/" + pdfFileName(j); j.PDFPath != want {
+			t.Errorf("judgment %d: PDFPath = %q, want %q", i, j.PDFPath, want)
+		}
+	}
+	if p := js[8].PDFPath; p != "" {
+		t.Errorf("a judgment without a PDF link got PDFPath %q", p)
+	}
+}
@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrYearOutOfRange is returned for years outside MinYear..MaxYear.
+	ErrYearOutOfRange = errors.New("year out of supported range")
+	// ErrNoJudgments is returned when a year page has no judgment rows,
+	// which usually means the page could not be parsed.
+	ErrNoJudgments = errors.New("no judgments found")
+	// ErrEmptyYear is returned instead of ErrNoJudgments when the page has
+	// no judgment rows and says so ("No records found"): the year is
+	// legitimately empty.
+	ErrEmptyYear = errors.New("site lists no judgments for the year")
+	// ErrNoMatches is returned when a year has judgments but none pass the
+	// subject or date filter.
+	ErrNoMatches = errors.New("no judgments matched")
+	// ErrNoDateMatches wraps ErrNoMatches for a year whose judgments are
+	// all dated outside FromDate..ToDate.
+	ErrNoDateMatches = fmt.Errorf("%w date filter", ErrNoMatches)
+	// ErrPartial is returned, wrapped with ctx.Err(), for a year that was
+	// interrupted with Scraper.KeepPartial set after some judgments were
+	// read, or wrapped with the page's error for one whose later result page
+	// failed (see Scraper.PageRetries); ScrapeYear has persisted those
+	// judgments.
+	ErrPartial = errors.New("interrupted with partial results")
+)
+
+// FetchError reports a failed request for a year page, either a network
+// error (StatusCode 0) or a non-200 response.
+type FetchError struct {
+	StatusCode int
+	URL        string
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("fetch %s: %v", e.URL, e.Err)
+	}
+	return fmt.Sprintf("fetch failed: %v", e.Err)
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// IsRetryable reports whether err is worth retrying: network errors, 429 and
+// 5xx responses, and per-attempt timeouts. Other 4xx responses, pages without
+// judgments and cancellations are permanent.
+func IsRetryable(err error) bool {
+	var fe *FetchError
+	if errors.As(err, &fe) {
+		return fe.StatusCode == 0 || fe.StatusCode == http.StatusTooManyRequests || fe.StatusCode >= 500
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTransientErrorsAreRetried(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		y := r.URL.Query().Get("judgment_year")
+		mu.Lock()
+		hits[y]++
+		mu.Unlock()
+		switch y {
+		case "2016":
+			http.Error(w, "oops", http.StatusInternalServerError)
+		case "2017":
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case "2018":
+			http.NotFound(w, r)
+		default:
+			io.WriteString(w, yearPage())
+		}
+	}))
+	defer srv.Close()
+	sc := &Scraper{BaseURL: srv.URL, HTTPClient: srv.Client(), Logger: discard, Retries: 2}
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), []int{2016, 2017, 2018, 2019}, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		year      int
+		status    int
+		retryable bool
+		hits      int
+	}{
+		{2016, http.StatusInternalServerError, true, 3},
+		{2017, http.StatusTooManyRequests, true, 3},
+		{2018, http.StatusNotFound, false, 1},
+		{2019, 0, false, 1},
+	} {
+		err := results[tc.year]
+		var fe *FetchError
+		switch {
+		case tc.status != 0 && (!errors.As(err, &fe) || fe.StatusCode != tc.status):
+			t.Errorf("year %d: %v, want a FetchError with status %d", tc.year, err, tc.status)
+		case tc.status == 0 && !errors.Is(err, ErrNoJudgments):
+			t.Errorf("year %d: %v, want ErrNoJudgments", tc.year, err)
+		}
+		if IsRetryable(err) != tc.retryable {
+			t.Errorf("year %d: IsRetryable = %t, want %t", tc.year, !tc.retryable, tc.retryable)
+		}
+		if got := hits[fmt.Sprint(tc.year)]; got != tc.hits {
+			t.Errorf("year %d requested %d times, want %d", tc.year, got, tc.hits)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&FetchError{Err: errors.New("connection refused")}, true},
+		{&FetchError{StatusCode: http.StatusBadGateway}, true},
+		{&FetchError{StatusCode: http.StatusForbidden}, false},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{ErrEmptyYear, false},
+		{nil, false},
+	} {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Errorf("IsRetryable(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2016: yearPage(row(1, "12-03-2016", "A vs B", "Tax", "s", "/a.pdf")),
+		2017: `<html><body><p>Judgments will appear here</p></body></html>`,
+		2018: `<html><body><table>` + tableHeader + `<tr><td colspan="6">No records found</td></tr></table></body></html>`,
+	}, map[string]string{"/a.pdf": pdfBody})
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	for _, tc := range []struct {
+		name   string
+		year   int
+		setup  func(*Scraper)
+		want   error
+		status int
+	}{
+		{"out of range", 2015, nil, ErrYearOutOfRange, 0},
+		{"no judgments", 2017, nil, ErrNoJudgments, 0},
+		{"empty year", 2018, nil, ErrEmptyYear, 0},
+		{"no subject matches", 2016, func(sc *Scraper) { sc.SubjectFilter = "Civil" }, ErrNoMatches, 0},
+		{"http status", 2019, nil, nil, http.StatusNotFound},
+		{"network", 2016, func(sc *Scraper) { sc.BaseURL = closed.URL }, nil, -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := s.scraper()
+			sc.PersistFunc = func(int, []Judgment) error { return nil }
+			if tc.setup != nil {
+				tc.setup(sc)
+			}
+			err := sc.ScrapeYear(tc.year, t.TempDir())
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want one wrapping %v", err, tc.want)
+			}
+			var fe *FetchError
+			switch {
+			case tc.status == 0 && errors.As(err, &fe):
+				t.Errorf("err = %v is a FetchError", err)
+			case tc.status > 0 && (!errors.As(err, &fe) || fe.StatusCode != tc.status || fe.URL == ""):
+				t.Errorf("err = %v, want a FetchError with status %d and its URL", err, tc.status)
+			case tc.status < 0 && (!errors.As(err, &fe) || fe.StatusCode != 0 || fe.Err == nil):
+				t.Errorf("err = %v, want a network FetchError", err)
+			}
+		})
+	}
+}
+
+func TestEmptyYear(t *testing.T) {
+	years := map[int]string{}
+	for i, msg := range []string{
+		`<table>` + tableHeader + `<tr><td colspan="6">No records found</td></tr></table>`,
+		`<table>` + tableHeader + `<tr><td class="dataTables_empty" colspan="6">No data available in table</td></tr></table>`,
+		`<div class="landmark_judgment_summary"><p>No Judgments Found.</p></div>`,
+		`<table><tr><th>Title</th></tr></table><p>No matching records found</p>`,
+	} {
+		years[2016+i] = `<html><body>` + msg + `</body></html>`
+	}
+	s := newSite(t, years, nil)
+	sc := s.scraper()
+	sc.Retries = 2
+	sc.PersistFunc = func(int, []Judgment) error {
+		t.Error("an empty year was persisted")
+		return nil
+	}
+	var list []int
+	for y := range years {
+		list = append(list, y)
+	}
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), list, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y, err := range results {
+		if !errors.Is(err, ErrEmptyYear) || errors.Is(err, ErrNoJudgments) {
+			t.Errorf("year %d (%s): err = %v, want ErrEmptyYear", y, years[y], err)
+		}
+	}
+	if n := s.hits("/judgments/"); n != len(years) {
+		t.Errorf("made %d requests for %d empty years, want no retries", n, len(years))
+	}
+
+	if _, err := sc.SelfTest(context.Background(), SelfTestOptions{Year: 2016}); !errors.Is(err, ErrEmptyYear) || errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("selftest of an empty year: %v, want ErrEmptyYear rather than a layout change", err)
+	}
+}
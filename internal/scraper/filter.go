@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FilterDateRange returns the judgments whose date falls within from..to,
+// both inclusive. Judgments with an empty or unparseable date are dropped.
+func FilterDateRange(judgments []Judgment, from, to time.Time) []Judgment {
+	return filterDates(judgments, from, to, false)
+}
+
+// filterDates keeps the judgments for which inDateRange holds.
+func filterDates(judgments []Judgment, from, to time.Time, keepUnparsed bool) []Judgment {
+	var kept []Judgment
+	for _, j := range judgments {
+		if inDateRange(j, from, to, keepUnparsed) {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+// inDateRange reports whether j is dated within from..to, both inclusive; a
+// zero bound is open. A judgment without a parseable date is in range only
+// when keepUnparsed is set.
+func inDateRange(j Judgment, from, to time.Time, keepUnparsed bool) bool {
+	t, ok := judgmentTime(j)
+	if !ok {
+		return keepUnparsed
+	}
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// FilterSubject returns the judgments whose Subject contains keyword as a
+// case-insensitive substring.
+func FilterSubject(judgments []Judgment, keyword string) []Judgment {
+	keyword = strings.ToLower(keyword)
+	var kept []Judgment
+	for _, j := range judgments {
+		if strings.Contains(strings.ToLower(j.Subject), keyword) {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+// UniqueSubjects returns the distinct subjects of judgments, sorted. Subjects
+// are compared with whitespace collapsed and case folded; the first spelling
+// seen is kept. Empty subjects are skipped.
+func UniqueSubjects(judgments []Judgment) []string {
+	seen := map[string]bool{}
+	var subjects []string
+	for _, j := range judgments {
+		subject := normalizeWhitespace(j.Subject)
+		key := strings.ToLower(subject)
+		if subject == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		subjects = append(subjects, subject)
+	}
+	slices.SortFunc(subjects, func(a, b string) int {
+		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return subjects
+}
+
+// PDFLinks returns the distinct non-empty PDF links of judgments, sorted.
+func PDFLinks(judgments []Judgment) []string {
+	seen := map[string]bool{}
+	var links []string
+	for _, j := range judgments {
+		if j.PDFLink == "" || seen[j.PDFLink] {
+			continue
+		}
+		seen[j.PDFLink] = true
+		links = append(links, j.PDFLink)
+	}
+	slices.Sort(links)
+	return links
+}
@@ -0,0 +1,314 @@
+package scraper
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// subjectsOf returns the subjects of judgments, in order.
+func subjectsOf(js []Judgment) []string {
+	var out []string
+	for _, j := range js {
+		out = append(out, j.Subject)
+	}
+	return out
+}
+
+func TestSubjectFilter(t *testing.T) {
+	page := yearPage(
+		row(1, "12-03-2018", "A vs B", "Income TAX", "s", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Land Acquisition", "s", "/b.pdf"),
+		row(3, "14-03-2018", "E vs F", "service tax; excise", "s", "/c.pdf"),
+	)
+	s := newSite(t, map[int]string{2018: page}, nil)
+	for _, tc := range []struct {
+		keyword string
+		want    []string
+	}{
+		{"tax", []string{"Income TAX", "service tax; excise"}},
+		{"TAX", []string{"Income TAX", "service tax; excise"}},
+		{"land acq", []string{"Land Acquisition"}},
+		{"", []string{"Income TAX", "Land Acquisition", "service tax; excise"}},
+	} {
+		sc := s.scraper()
+		sc.SubjectFilter = tc.keyword
+		js, err := sc.FetchYear(2018)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.keyword, err)
+		}
+		if got := subjectsOf(js); !slices.Equal(got, tc.want) {
+			t.Errorf("SubjectFilter %q kept %q, want %q", tc.keyword, got, tc.want)
+		}
+		if got := subjectsOf(FilterSubject(append([]Judgment(nil), js...), tc.keyword)); !slices.Equal(got, tc.want) {
+			t.Errorf("FilterSubject %q kept %q, want %q", tc.keyword, got, tc.want)
+		}
+	}
+
+	sc := s.scraper()
+	sc.SubjectFilter = "criminal"
+	if _, err := sc.FetchYear(2018); !errors.Is(err, ErrNoMatches) {
+		t.Errorf("a subject without matches: %v, want ErrNoMatches", err)
+	}
+}
+
+func TestDateRangeFilter(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "28-02-2018", "before", "Tax", "s", "/a.pdf"),
+		row(2, "01-03-2018", "first day", "Tax", "s", "/b.pdf"),
+		row(3, "31-03-2018", "last day", "Tax", "s", "/c.pdf"),
+		row(4, "01-04-2018", "after", "Tax", "s", "/d.pdf"),
+		row(5, "sometime in March", "undated", "Tax", "s", "/e.pdf"),
+	)}, nil)
+	from, to := mustDate(t, "2018-03-01"), mustDate(t, "2018-03-31")
+	for _, tc := range []struct {
+		name         string
+		from, to     string
+		dropUnparsed bool
+		want         []string
+	}{
+		{"inclusive bounds", "2018-03-01", "2018-03-31", false, []string{"first day", "last day", "undated"}},
+		{"drop unparsed", "2018-03-01", "2018-03-31", true, []string{"first day", "last day"}},
+		{"open start", "", "2018-03-01", true, []string{"before", "first day"}},
+		{"open end", "2018-03-31", "", false, []string{"last day", "after", "undated"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := s.scraper()
+			if tc.from != "" {
+				sc.FromDate = mustDate(t, tc.from)
+			}
+			if tc.to != "" {
+				sc.ToDate = mustDate(t, tc.to)
+			}
+			sc.DropUnparsedDates = tc.dropUnparsed
+			js, err := sc.FetchYear(2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, j := range js {
+				got = append(got, j.CauseTitleCaseNo)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("kept %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	all, err := s.scraper().FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FilterDateRange(all, from, to); len(got) != 2 {
+		t.Errorf("FilterDateRange kept %d judgments, want the 2 dated in range", len(got))
+	}
+	sc := s.scraper()
+	sc.FromDate, sc.ToDate, sc.DropUnparsedDates = mustDate(t, "2019-01-01"), mustDate(t, "2019-12-31"), true
+	if _, err := sc.FetchYear(2018); !errors.Is(err, ErrNoDateMatches) || !errors.Is(err, ErrNoMatches) {
+		t.Errorf("a range without judgments: %v, want ErrNoDateMatches wrapping ErrNoMatches", err)
+	}
+}
+
+func TestPDFFilter(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2018: yearPage(
+			row(1, "12-03-2018", "linked", "Tax", "s", "/a.pdf"),
+			row(2, "13-03-2018", "unlinked", "Tax", "s", ""),
+			row(3, "14-03-2018", "linked civil", "Civil", "s", "/c.pdf"),
+			row(4, "15-03-2018", "unlinked civil", "Civil", "s", ""),
+		),
+		2019: yearPage(row(1, "12-03-2019", "linked", "Tax", "s", "/a.pdf")),
+	}, nil)
+	titles := func(js []Judgment) []string {
+		var out []string
+		for _, j := range js {
+			out = append(out, j.CauseTitleCaseNo)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name    string
+		filter  PDFFilter
+		subject string
+		want    []string
+	}{
+		{"any", PDFAny, "", []string{"linked", "unlinked", "linked civil", "unlinked civil"}},
+		{"required", PDFRequired, "", []string{"linked", "linked civil"}},
+		{"missing", PDFMissing, "", []string{"unlinked", "unlinked civil"}},
+		{"missing with a subject", PDFMissing, "civil", []string{"unlinked civil"}},
+		{"required with a subject", PDFRequired, "tax", []string{"linked"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &captureLogger{}
+			sc := s.scraper()
+			sc.Logger, sc.PDFFilter, sc.SubjectFilter = logger, tc.filter, tc.subject
+			js, err := sc.FetchYear(2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(js); !slices.Equal(got, tc.want) {
+				t.Errorf("kept %q, want %q", got, tc.want)
+			}
+			if tc.filter != PDFAny && !logger.contains("dropped ") {
+				t.Errorf("no message about the dropped judgments in %q", logger.lines)
+			}
+		})
+	}
+
+	sc := s.scraper()
+	sc.PDFFilter = PDFMissing
+	_, err := sc.FetchYear(2019)
+	if !errors.Is(err, ErrNoMatches) || errors.Is(err, ErrNoJudgments) {
+		t.Errorf("a year left empty by the PDF filter: %v, want ErrNoMatches", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "PDF link filter") {
+		t.Errorf("error %v does not name the PDF link filter", err)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf") +
+		row(2, "13-03-2018", "C vs D", "Civil", "s", "/b.pdf") +
+		row(3, "14-03-2018", "E vs F", "Tax", "s", "/c.pdf") +
+		row(4, "15-03-2018", "G vs H", "Tax", "s", "/d.pdf") + `</table></body></html>`
+	page2 := yearPage(
+		row(5, "16-03-2018", "I vs J", "Tax", "s", "/e.pdf"),
+		row(6, "17-03-2018", "K vs L", "Tax", "s", "/f.pdf"),
+	)
+	for _, tc := range []struct {
+		name    string
+		limit   int
+		subject string
+		want    int
+		page2   int
+	}{
+		{"cap on page one", 3, "", 3, 0},
+		{"cap counts kept rows", 3, "tax", 3, 0},
+		{"cap on page two", 5, "", 5, 1},
+		{"cap above the total", 10, "", 6, 1},
+		{"unlimited", 0, "", 6, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSite(t, map[int]string{2018: page1}, map[string]string{"/p2": page2})
+			logger := &captureLogger{}
+			sc := s.scraper()
+			sc.Logger = logger
+			sc.Limit, sc.SubjectFilter = tc.limit, tc.subject
+			js, err := sc.FetchYear(2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(js) != tc.want {
+				t.Errorf("kept %d judgments, want %d", len(js), tc.want)
+			}
+			if tc.subject != "" && slices.Contains(subjectsOf(js), "Civil") {
+				t.Errorf("kept %q, want only matching subjects", subjectsOf(js))
+			}
+			if n := s.hits("/p2"); n != tc.page2 {
+				t.Errorf("fetched the second page %d times, want %d", n, tc.page2)
+			}
+			if stopped := logger.contains("stopped at the limit"); stopped != (tc.want == tc.limit) {
+				t.Errorf("logged %q; want the limit message only when the cap is hit", logger.lines)
+			}
+		})
+	}
+}
+
+func TestLimitAfterDedupAndSeen(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf") +
+		row(2, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf") +
+		row(3, "13-03-2018", "C vs D", "Tax", "s", "/b.pdf") + `</table></body></html>`
+	page2 := yearPage(
+		row(4, "14-03-2018", "E vs F", "Tax", "s", "/c.pdf"),
+		row(5, "15-03-2018", "G vs H", "Tax", "s", "/d.pdf"),
+	)
+	for _, tc := range []struct {
+		name  string
+		dedup bool
+		seen  []string
+		want  []string
+	}{
+		{"dedup", true, nil, []string{"/a.pdf", "/b.pdf", "/c.pdf"}},
+		{"seen", false, []string{"/a.pdf"}, []string{"/b.pdf", "/c.pdf", "/d.pdf"}},
+		{"dedup and seen", true, []string{"/b.pdf"}, []string{"/a.pdf", "/c.pdf", "/d.pdf"}},
+		{"nothing new", false, []string{"/a.pdf", "/b.pdf", "/c.pdf", "/d.pdf"}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSite(t, map[int]string{2018: page1}, map[string]string{"/p2": page2})
+			sc := s.scraper()
+			sc.Limit, sc.Dedup = 3, tc.dedup
+			sc.Seen = &SeenState{}
+			for _, l := range tc.seen {
+				sc.Seen.Record(2018, []Judgment{{PDFLink: s.URL + l}})
+			}
+			js, err := sc.FetchYear(2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, j := range js {
+				got = append(got, strings.TrimPrefix(j.PDFLink, s.URL))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("kept %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUniqueSubjects(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2017: yearPage(
+			row(1, "12-03-2017", "A vs B", "Tax", "s", "/a.pdf"),
+			row(2, "13-03-2017", "C vs D", "  Service   Law ", "s", "/b.pdf"),
+			row(3, "14-03-2017", "E vs F", "", "s", "/c.pdf"),
+		),
+		2018: yearPage(
+			row(1, "12-03-2018", "G vs H", "TAX", "s", "/d.pdf"),
+			row(2, "13-03-2018", "I vs J", "Service\nlaw", "s", "/e.pdf"),
+			row(3, "14-03-2018", "K vs L", "arbitration", "s", "/f.pdf"),
+			row(4, "15-03-2018", "M vs N", "Criminal", "s", "/g.pdf"),
+		),
+	}, nil)
+	var js []Judgment
+	for _, y := range []int{2017, 2018} {
+		got, err := s.scraper().FetchYear(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		js = append(js, got...)
+	}
+	// the first spelling of each is kept, sorted ignoring case
+	want := []string{"arbitration", "Criminal", "Service Law", "Tax"}
+	if got := UniqueSubjects(js); !slices.Equal(got, want) {
+		t.Errorf("UniqueSubjects = %q, want %q", got, want)
+	}
+	if got := UniqueSubjects([]Judgment{{Subject: " "}, {}}); got != nil {
+		t.Errorf("UniqueSubjects of blank subjects = %q, want nil", got)
+	}
+}
+
+func TestPDFLinks(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/b.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s", "/a.pdf"),
+		// the same file, linked to another page of it
+		row(3, "14-03-2018", "E vs F", "Tax", "s", "/a.pdf#page=2"),
+		row(4, "15-03-2018", "G vs H", "Tax", "s", ""),
+		row(5, "16-03-2018", "I vs J", "Tax", "s", "view-pdf/?id=7"),
+	)}, nil)
+	js, err := s.scraper().FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{s.URL + "/a.pdf", s.URL + "/b.pdf", s.URL + "/judgments/view-pdf/?id=7"}
+	if got := PDFLinks(js); !slices.Equal(got, want) {
+		t.Errorf("PDFLinks = %q, want %q", got, want)
+	}
+	if got := PDFLinks([]Judgment{{}, {Subject: "no link"}}); got != nil {
+		t.Errorf("PDFLinks without links = %q, want nil", got)
+	}
+}
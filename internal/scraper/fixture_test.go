@@ -0,0 +1,136 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tableHeader is the header row of the site's judgments table.
+const tableHeader = `<tr><th>S.No</th><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th></tr>`
+
+// row returns a data row of the judgments table; an empty pdf leaves the
+// View cell without a link.
+func row(n int, date, title, subject, summary, pdf string) string {
+	view := ""
+	if pdf != "" {
+		view = fmt.Sprintf(`<a href="%s">PDF</a>`, pdf)
+	}
+	return fmt.Sprintf("<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>", n, date, title, subject, summary, view)
+}
+
+// yearPage returns a year page whose table holds rows after the header.
+func yearPage(rows ...string) string {
+	return `<html><body><div class="landmark_judgment_summary"><table>` + tableHeader + strings.Join(rows, "") + `</table></div></body></html>`
+}
+
+// site is a fixture of the judgments site: it serves pages by path, PDFs
+// for paths ending in .pdf, and the page of each year given by its
+// judgment_year parameter. It records every request.
+type site struct {
+	*httptest.Server
+	mu       sync.Mutex
+	pages    map[string]string
+	years    map[string]string
+	requests []*http.Request
+	// arrived holds when each request came in
+	arrived []time.Time
+	// lastModified, when set, is sent as the Last-Modified header of the
+	// pages at its paths
+	lastModified map[string]string
+}
+
+// newSite starts a site serving years, keyed by year, at /judgments/ and
+// pages by path. It is closed when the test ends.
+func newSite(t *testing.T, years map[int]string, pages map[string]string) *site {
+	t.Helper()
+	s := &site{pages: map[string]string{}, years: map[string]string{}}
+	for y, body := range years {
+		s.years[fmt.Sprint(y)] = body
+	}
+	for p, body := range pages {
+		s.pages[p] = body
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *site) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Clone(r.Context()))
+	s.arrived = append(s.arrived, time.Now())
+	body, ok := s.pages[r.URL.Path]
+	if !ok && r.URL.Path == "/judgments/" {
+		body, ok = s.years[r.URL.Query().Get("judgment_year")]
+	}
+	lastModified := s.lastModified[r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if lastModified != "" {
+		w.Header().Set("Last-Modified", lastModified)
+	}
+	if strings.HasSuffix(r.URL.Path, ".pdf") {
+		w.Header().Set("Content-Type", "application/pdf")
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	io.WriteString(w, body)
+}
+
+// hits returns how many requests were made for path.
+func (s *site) hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r.URL.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// minGap returns the shortest time between two requests, in the order they
+// arrived.
+func (s *site) minGap() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gap := time.Duration(math.MaxInt64)
+	for i := 1; i < len(s.arrived); i++ {
+		gap = min(gap, s.arrived[i].Sub(s.arrived[i-1]))
+	}
+	return gap
+}
+
+// discard is a logger that drops every message.
+var discard = log.New(io.Discard, "", 0)
+
+// scraper returns a scraper for the site that logs nothing and is not rate
+// limited.
+func (s *site) scraper() *Scraper {
+	return &Scraper{BaseURL: s.URL + "/judgments/", HTTPClient: s.Client(), Logger: discard}
+}
+
+// pdfBody is served for fixture PDFs.
+const pdfBody = "%PDF-1.4 fixture"
+
+// mustDate parses a YYYY-MM-DD date.
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
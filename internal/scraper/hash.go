@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hash returns a SHA-256 hex digest of the judgment's content: its date,
+// cause title, subject, summaries, links and bench, each with whitespace collapsed
+// and the links normalized as for Dedup. Fields describing the scrape rather
+// than the judgment, such as SourceLastModified and PDFOK, are left out, so
+// the hash only changes when the judgment itself does.
+func (j Judgment) Hash() string {
+	h := sha256.New()
+	for _, f := range []string{
+		normalizeWhitespace(j.DateOfJudgment),
+		normalizeWhitespace(j.CauseTitleCaseNo),
+		normalizeWhitespace(j.Subject),
+		normalizeWhitespace(j.JudgmentSummary),
+		normalizeLink(j.PDFLink),
+		normalizeLink(j.DetailLink),
+		normalizeWhitespace(j.SummaryEN),
+		normalizeWhitespace(j.SummaryHI),
+	} {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+	}
+	// only hashed when present, so judgments without a bench keep the
+	// hashes they had before Bench was parsed
+	for _, name := range j.Bench {
+		h.Write([]byte("bench:" + normalizeWhitespace(name)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DiffJudgments compares two scrapes of the same judgments. Judgments are
+// matched by the identity used by Dedup (PDF link, else date and cause
+// title); matched judgments whose Hash differs are changed. added and
+// changed hold judgments from new in its order, removed those from old.
+func DiffJudgments(old, new []Judgment) (added, removed, changed []Judgment) {
+	oldHash := make(map[string]string, len(old))
+	for _, j := range old {
+		oldHash[dedupKey(j)] = j.Hash()
+	}
+	inNew := make(map[string]bool, len(new))
+	for _, j := range new {
+		key := dedupKey(j)
+		inNew[key] = true
+		h, ok := oldHash[key]
+		switch {
+		case !ok:
+			added = append(added, j)
+		case h != j.Hash():
+			changed = append(changed, j)
+		}
+	}
+	for _, j := range old {
+		if !inNew[dedupKey(j)] {
+			removed = append(removed, j)
+		}
+	}
+	return added, removed, changed
+}
@@ -0,0 +1,47 @@
+package scraper
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	j := Judgment{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A vs B", Subject: "Tax", JudgmentSummary: "held", PDFLink: "https://example.org/a.pdf"}
+	same := j
+	same.JudgmentSummary = "  held\n"
+	ok := true
+	same.PDFOK = &ok
+	same.SourceLastModified = "Mon, 12 Mar 2018 10:00:00 GMT"
+	if j.Hash() != same.Hash() {
+		t.Error("whitespace or scrape metadata changed the hash")
+	}
+	edited := j
+	edited.JudgmentSummary = "reversed"
+	if j.Hash() == edited.Hash() {
+		t.Error("an edited summary kept the hash")
+	}
+}
+
+func TestDiffJudgments(t *testing.T) {
+	kept := Judgment{CauseTitleCaseNo: "A vs B", JudgmentSummary: "s1", PDFLink: "https://example.org/a.pdf"}
+	edited := Judgment{CauseTitleCaseNo: "C vs D", JudgmentSummary: "s2", PDFLink: "https://example.org/c.pdf"}
+	gone := Judgment{CauseTitleCaseNo: "E vs F", JudgmentSummary: "s3", PDFLink: "https://example.org/e.pdf"}
+	fresh := Judgment{CauseTitleCaseNo: "G vs H", JudgmentSummary: "s4", PDFLink: "https://example.org/g.pdf"}
+	newEdited := edited
+	newEdited.JudgmentSummary = "s2, corrected"
+
+	added, removed, changed := DiffJudgments(
+		[]Judgment{kept, edited, gone},
+		[]Judgment{fresh, kept, newEdited},
+	)
+	if len(added) != 1 || added[0].PDFLink != fresh.PDFLink {
+		t.Errorf("added = %v, want the new row", added)
+	}
+	if len(removed) != 1 || removed[0].PDFLink != gone.PDFLink {
+		t.Errorf("removed = %v, want the dropped row", removed)
+	}
+	if len(changed) != 1 || changed[0].JudgmentSummary != "s2, corrected" {
+		t.Errorf("changed = %v, want the edited row as it is now", changed)
+	}
+
+	if a, r, c := DiffJudgments([]Judgment{kept}, []Judgment{kept}); a != nil || r != nil || c != nil {
+		t.Errorf("identical scrapes differ: %v %v %v", a, r, c)
+	}
+}
@@ -0,0 +1,63 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+)
+
+// setHeaders sets the User-Agent on req, then RequestHeaders, which may
+// override it.
+func (sc *Scraper) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", sc.userAgent())
+	for k, v := range sc.RequestHeaders {
+		req.Header.Set(k, v)
+	}
+}
+
+// jarMu guards the cookie jar of every Scraper, made on first use.
+var jarMu sync.Mutex
+
+// withCookies returns a shallow copy of c using the scraper's cookie jar,
+// which starts out holding Cookies for the base URL, or c itself when there
+// are no Cookies or c has its own jar.
+func (sc *Scraper) withCookies(c *http.Client) *http.Client {
+	if len(sc.Cookies) == 0 || c.Jar != nil {
+		return c
+	}
+	jar := sc.cookieJar()
+	if jar == nil {
+		return c
+	}
+	withJar := *c
+	withJar.Jar = jar
+	return &withJar
+}
+
+// cookieJar returns the scraper's cookie jar, making it on the first call,
+// or nil when the base URL does not parse.
+func (sc *Scraper) cookieJar() http.CookieJar {
+	jarMu.Lock()
+	defer jarMu.Unlock()
+	if sc.jar != nil {
+		return sc.jar
+	}
+	base := sc.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil
+	}
+	// set on the site's root so that cookies without a Path reach every
+	// page and PDF, not only those under the base URL's directory
+	jar.SetCookies(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}, sc.Cookies)
+	sc.jar = jar
+	return jar
+}
@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgentReachesServer(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		ua      string
+		headers map[string]string
+		want    string
+	}{
+		{"default", "", nil, DefaultUserAgent},
+		{"configured", "research-bot/2.0 (+mailto:a@example.org)", nil, "research-bot/2.0 (+mailto:a@example.org)"},
+		{"header overrides", "research-bot/2.0", map[string]string{"User-Agent": "other/1"}, "other/1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSite(t, map[int]string{2018: yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))}, map[string]string{"/a.pdf": pdfBody})
+			sc := s.scraper()
+			sc.UserAgent, sc.RequestHeaders = tc.ua, tc.headers
+			sc.VerifyPDFs = true
+			sc.PersistFunc = func(int, []Judgment) error { return nil }
+			if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for _, r := range s.requests {
+				if got := r.Header.Get("User-Agent"); got != tc.want {
+					t.Errorf("%s sent User-Agent %q, want %q", r.URL.Path, got, tc.want)
+				}
+			}
+			if len(s.requests) != 2 {
+				t.Errorf("made %d requests, want the page and the PDF check", len(s.requests))
+			}
+		})
+	}
+}
+
+func TestHeadersAndCookiesReachServer(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") + `</table></body></html>`
+	page2 := yearPage(row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf"))
+	type seen struct{ header, user, session string }
+	got := map[string]seen{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := seen{header: r.Header.Get("X-Token")}
+		if c, err := r.Cookie("user"); err == nil {
+			s.user = c.Value
+		}
+		if c, err := r.Cookie("session"); err == nil {
+			s.session = c.Value
+		}
+		got[r.URL.Path] = s
+		switch r.URL.Path {
+		case "/judgments/":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+			io.WriteString(w, page1)
+		case "/p2":
+			io.WriteString(w, page2)
+		default:
+			w.Header().Set("Content-Type", "application/pdf")
+			io.WriteString(w, pdfBody)
+		}
+	}))
+	defer srv.Close()
+
+	sc := NewScraper(
+		WithBaseURL(srv.URL+"/judgments/"),
+		WithHeaders(map[string]string{"X-Token": "t0"}),
+		WithCookies(&http.Cookie{Name: "user", Value: "u1"}),
+		WithLogger(log.New(io.Discard, "", 0)),
+	)
+	sc.VerifyPDFs = true
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]seen{
+		"/judgments/": {header: "t0", user: "u1"},
+		"/p2":         {header: "t0", user: "u1", session: "s1"},
+		"/a.pdf":      {header: "t0", user: "u1", session: "s1"},
+		"/b.pdf":      {header: "t0", user: "u1", session: "s1"},
+	} {
+		if got[path] != want {
+			t.Errorf("%s received %+v, want %+v", path, got[path], want)
+		}
+	}
+}
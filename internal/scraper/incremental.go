@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+// SeenState records, by year, the judgments earlier incremental runs have
+// emitted, so that a later run can keep only those it has not seen. A
+// judgment is identified as by Dedup (PDF link, else date and cause title),
+// so one whose summary is edited on the site is not emitted again. It is
+// safe for concurrent use.
+type SeenState struct {
+	mu    sync.Mutex
+	years map[int]map[string]bool
+}
+
+// seenFile is the JSON form of a SeenState: the sorted judgment IDs of each
+// year.
+type seenFile struct {
+	Years map[int][]string `json:"years"`
+}
+
+// LoadSeenState reads a state file written by Save. A missing file is an
+// empty state.
+func LoadSeenState(path string) (*SeenState, error) {
+	st := &SeenState{years: map[int]map[string]bool{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f seenFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for y, ids := range f.Years {
+		st.years[y] = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			st.years[y][id] = true
+		}
+	}
+	return st, nil
+}
+
+// Unseen returns the judgments of year the state has not recorded, in
+// their order. It does not record them; call Record once they are written.
+func (st *SeenState) Unseen(year int, judgments []Judgment) []Judgment {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var out []Judgment
+	for _, j := range judgments {
+		if !st.years[year][seenID(j)] {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// has reports whether j is recorded as seen for year.
+func (st *SeenState) has(year int, j Judgment) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.years[year][seenID(j)]
+}
+
+// Record adds judgments to the state as seen for year.
+func (st *SeenState) Record(year int, judgments []Judgment) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.years == nil {
+		st.years = map[int]map[string]bool{}
+	}
+	if st.years[year] == nil {
+		st.years[year] = map[string]bool{}
+	}
+	for _, j := range judgments {
+		st.years[year][seenID(j)] = true
+	}
+}
+
+// Reset forgets what was recorded for year, so that the next Record
+// replaces it.
+func (st *SeenState) Reset(year int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.years, year)
+}
+
+// Save writes the state to path atomically, so that a run killed mid-write
+// leaves the previous state intact.
+func (st *SeenState) Save(path string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	f := seenFile{Years: make(map[int][]string, len(st.years))}
+	for y, ids := range st.years {
+		f.Years[y] = slices.Sorted(maps.Keys(ids))
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(f)
+	})
+}
+
+// seenID is the identity of j recorded in a SeenState: a digest of its
+// Dedup key, which keeps the state file small.
+func seenID(j Judgment) string {
+	sum := sha256.Sum256([]byte(dedupKey(j)))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSeenState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st, err := LoadSeenState(path)
+	if err != nil {
+		t.Fatalf("a missing state file: %v", err)
+	}
+	js := syntheticJudgments(3)
+	if got := st.Unseen(2018, js); len(got) != 3 {
+		t.Errorf("an empty state hid %d judgments", 3-len(got))
+	}
+	st.Record(2018, js[:2])
+	if err := st.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err = LoadSeenState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := js[0]
+	edited.JudgmentSummary = "edited on the site"
+	untitled := Judgment{DateOfJudgment: "01-02-2018", CauseTitleCaseNo: "No PDF  vs State"}
+	st.Record(2018, []Judgment{untitled})
+	retitled := untitled
+	retitled.CauseTitleCaseNo = "No PDF vs State"
+	got := st.Unseen(2018, []Judgment{edited, js[1], js[2], retitled})
+	if len(got) != 1 || got[0].PDFLink != js[2].PDFLink {
+		t.Errorf("Unseen = %+v, want only the judgment not recorded", got)
+	}
+	if got := st.Unseen(2019, js); len(got) != 3 {
+		t.Errorf("judgments recorded for 2018 were hidden in 2019: %d unseen", len(got))
+	}
+
+	st.Reset(2018)
+	if got := st.Unseen(2018, js); len(got) != 3 {
+		t.Errorf("after Reset %d judgments are still seen", 3-len(got))
+	}
+	st.Record(2018, js[2:])
+	if got := st.Unseen(2018, js); len(got) != 2 {
+		t.Errorf("after Reset and Record: %d unseen, want 2", len(got))
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSeenState(path); err == nil {
+		t.Error("a corrupt state file loaded")
+	}
+	var zero SeenState
+	zero.Record(2018, js)
+	if got := zero.Unseen(2018, js); len(got) != 0 {
+		t.Errorf("the zero SeenState did not record: %d unseen", len(got))
+	}
+}
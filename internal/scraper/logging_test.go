@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureLogger records every message it is given.
+type captureLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+// contains reports whether a recorded message contains s.
+func (l *captureLogger) contains(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.lines {
+		if strings.Contains(line, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoggerReceivesProgress(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s", "/b.pdf"),
+	)}, nil)
+	logger := &captureLogger{}
+	sc := NewScraper(WithBaseURL(s.URL+"/judgments/"), WithLogger(logger))
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if !logger.contains("year 2018: parsed 2 judgments") {
+		t.Errorf("logged %q, want the parse count", logger.lines)
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	page := `<html><body><p>Showing 3 judgments.</p><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf") + `</table></body></html>`
+	s := newSite(t, map[int]string{2018: page}, nil)
+	for _, tc := range []struct {
+		level   LogLevel
+		present []string
+		absent  []string
+	}{
+		{LogQuiet, []string{"advertises 3 judgments"}, []string{"parsed 1 judgments", "fetching "}},
+		{LogNormal, []string{"advertises 3 judgments", "parsed 1 judgments"}, []string{"fetching ", "table rows"}},
+		{LogVerbose, []string{"advertises 3 judgments", "parsed 1 judgments", "fetching " + s.URL, "2 table rows"}, nil},
+	} {
+		logger := &captureLogger{}
+		sc := s.scraper()
+		sc.Logger, sc.LogLevel = logger, tc.level
+		sc.PersistFunc = func(int, []Judgment) error { return nil }
+		if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tc.present {
+			if !logger.contains(want) {
+				t.Errorf("level %d: no message containing %q in %q", tc.level, want, logger.lines)
+			}
+		}
+		for _, unwanted := range tc.absent {
+			if logger.contains(unwanted) {
+				t.Errorf("level %d: logged %q, which this level suppresses", tc.level, unwanted)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MergeFiles combines the judgments of existing JSON and NDJSON output files,
+// which may be gzip-compressed (named .gz), into one file at out. Judgments
+// are deduplicated as by Dedup, keeping the first occurrence in the order of
+// paths, and sorted by year, judgment date and then identity, so the result
+// does not depend on the order of the inputs' rows. out is written as NDJSON
+// when named .ndjson and as a JSON array otherwise. No network access is
+// made.
+func MergeFiles(paths []string, out string) error {
+	var all []Judgment
+	for _, path := range paths {
+		js, err := readJudgmentsFile(path)
+		if err != nil {
+			return err
+		}
+		all = append(all, js...)
+	}
+	all = Dedup(all)
+	slices.SortStableFunc(all, func(a, b Judgment) int {
+		ta, okA := judgmentTime(a)
+		tb, okB := judgmentTime(b)
+		byDate := 0
+		switch {
+		case okA && okB:
+			byDate = ta.Compare(tb)
+		case okA:
+			byDate = -1
+		case okB:
+			byDate = 1
+		}
+		return cmp.Or(cmp.Compare(a.Year, b.Year), byDate, strings.Compare(dedupKey(a), dedupKey(b)))
+	})
+
+	encode := EncodeJudgments
+	if strings.EqualFold(filepath.Ext(out), ".ndjson") {
+		encode = encodeNDJSON
+	}
+	if dir := filepath.Dir(out); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(out, func(w io.Writer) error { return encode(w, all) })
+}
+
+// readJudgmentsFile reads a JSON array or NDJSON file of judgments,
+// decompressing .gz files.
+func readJudgmentsFile(path string) ([]Judgment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return decodeJudgments(path, data)
+}
+
+// decodeJudgments decodes a JSON array or NDJSON stream of judgments, telling
+// them apart by the first non-space byte; name prefixes errors.
+func decodeJudgments(name string, data []byte) ([]Judgment, error) {
+	data = bytes.TrimSpace(data)
+	var judgments []Judgment
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &judgments); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return judgments, nil
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var j Judgment
+		if err := json.Unmarshal(line, &j); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, i+1, err)
+		}
+		judgments = append(judgments, j)
+	}
+	return judgments, nil
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	j := func(year int, date, title, pdf, summary string) Judgment {
+		return Judgment{Year: year, DateOfJudgment: date, CauseTitleCaseNo: title, PDFLink: pdf, JudgmentSummary: summary}
+	}
+	older := []Judgment{
+		j(2018, "20-05-2018", "C vs D", "https://example.org/c.pdf", "first copy"),
+		j(2017, "01-02-2017", "A vs B", "https://example.org/a.pdf", "s"),
+		j(2018, "", "Undated vs State", "", "s"),
+	}
+	newer := []Judgment{
+		j(2018, "20-05-2018", "C vs D", "https://example.org/c.pdf", "second copy"),
+		j(2018, "03-01-2018", "E vs F", "https://example.org/e.pdf", "s"),
+		j(2017, "01-02-2017", "A vs B", "https://example.org/a.pdf", "s"),
+	}
+	jsonPath := filepath.Join(dir, "old.json")
+	if err := (&Scraper{}).WriteYear(dir, 2018, older); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, YearFileName(2018, FormatJSON)), jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	ndjsonGz := filepath.Join(dir, YearFileName(2018, FormatNDJSON)+".gz")
+	if err := (&Scraper{Format: FormatNDJSON, Gzip: true}).WriteYear(dir, 2018, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, out := range []string{filepath.Join(dir, "merged.json"), filepath.Join(dir, "sub", "merged.ndjson")} {
+		if err := MergeFiles([]string{jsonPath, ndjsonGz}, out); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readJudgmentsFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, g := range got {
+			titles = append(titles, g.CauseTitleCaseNo)
+		}
+		if want := []string{"A vs B", "E vs F", "C vs D", "Undated vs State"}; !slices.Equal(titles, want) {
+			t.Errorf("%s: merged %q, want %q", filepath.Base(out), titles, want)
+		}
+		if len(got) == 4 && got[2].JudgmentSummary != "first copy" {
+			t.Errorf("%s: kept %q of the duplicate, want the first input's", filepath.Base(out), got[2].JudgmentSummary)
+		}
+	}
+
+	// the inputs' order only decides which duplicate is kept
+	a, b := filepath.Join(dir, "ab.json"), filepath.Join(dir, "ba.json")
+	if err := MergeFiles([]string{jsonPath, ndjsonGz}, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeFiles([]string{ndjsonGz, jsonPath}, b); err != nil {
+		t.Fatal(err)
+	}
+	ja, _ := readJudgmentsFile(a)
+	jb, _ := readJudgmentsFile(b)
+	for i := range ja {
+		if ja[i].PDFLink != jb[i].PDFLink || ja[i].CauseTitleCaseNo != jb[i].CauseTitleCaseNo {
+			t.Errorf("row %d differs with the inputs swapped: %q vs %q", i, ja[i].CauseTitleCaseNo, jb[i].CauseTitleCaseNo)
+		}
+	}
+
+	if err := MergeFiles([]string{filepath.Join(dir, "missing.json")}, filepath.Join(dir, "x.json")); err == nil {
+		t.Error("merging a missing file succeeded")
+	}
+}
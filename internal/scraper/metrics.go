@@ -0,0 +1,50 @@
+package scraper
+
+import (
+	"net/http"
+	"time"
+)
+
+// Metrics receives counts from a Scraper for monitoring. Implementations
+// must be safe for concurrent use. The package itself depends on no
+// metrics library; see internal/metrics for a Prometheus implementation.
+type Metrics interface {
+	// Request is called after every HTTP request with the response status,
+	// or 0 when the request failed without a response.
+	Request(status int)
+	// Parsed is called with the number of judgments parsed from a year page.
+	Parsed(year, judgments int)
+	// Written is called after a year's judgments were persisted.
+	Written(year, judgments int)
+	// Retry is called before a year is attempted again.
+	Retry(year int)
+	// ScrapeDone is called when ScrapeYear finishes a year, err being its
+	// result.
+	ScrapeDone(year int, took time.Duration, err error)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) Request(int)                          {}
+func (nopMetrics) Parsed(int, int)                      {}
+func (nopMetrics) Written(int, int)                     {}
+func (nopMetrics) Retry(int)                            {}
+func (nopMetrics) ScrapeDone(int, time.Duration, error) {}
+
+func (sc *Scraper) metrics() Metrics {
+	if sc.Metrics == nil {
+		return nopMetrics{}
+	}
+	return sc.Metrics
+}
+
+// do sends req with the scraper's client and records it in Metrics.
+func (sc *Scraper) do(req *http.Request) (*http.Response, error) {
+	resp, err := sc.client().Do(req)
+	if err != nil {
+		sc.metrics().Request(0)
+		return nil, err
+	}
+	sc.metrics().Request(resp.StatusCode)
+	return resp, nil
+}
@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMultiLang(t *testing.T) {
+	table := func(headers []string, cells ...string) string {
+		var b strings.Builder
+		b.WriteString("<html><body><table><tr>")
+		for _, h := range headers {
+			b.WriteString("<th>" + h + "</th>")
+		}
+		b.WriteString("</tr><tr>")
+		for _, c := range cells {
+			b.WriteString("<td>" + c + "</td>")
+		}
+		b.WriteString("</tr></table></body></html>")
+		return b.String()
+	}
+	std := []string{"Date of Judgment", "Cause Title", "Subject", "Summary", "View"}
+	pdf := `<a href="/a.pdf">PDF</a>`
+	for _, tc := range []struct {
+		name            string
+		page            string
+		multiLang       bool
+		summary, en, hi string
+	}{
+		{
+			name:      "language columns",
+			page:      table([]string{"Date of Judgment", "Cause Title", "Subject", "Summary (English)", "Summary (Hindi)", "View"}, "12-03-2018", "A vs B", "Tax", "in English", "हिंदी में", pdf),
+			multiLang: true,
+			// without a plain summary column the English one stands in
+			summary: "in English", en: "in English", hi: "हिंदी में",
+		},
+		{
+			name:      "Hindi header in Devanagari",
+			page:      table([]string{"Date of Judgment", "Cause Title", "Subject", "Summary", "सारांश हिंदी", "View"}, "12-03-2018", "A vs B", "Tax", "plain", "हिंदी में", pdf),
+			multiLang: true,
+			summary:   "plain", hi: "हिंदी में",
+		},
+		{
+			name:      "Hindi column only",
+			page:      table([]string{"Date of Judgment", "Cause Title", "Subject", "Hindi", "View"}, "12-03-2018", "A vs B", "Tax", "हिंदी में", pdf),
+			multiLang: true,
+			summary:   "हिंदी में", hi: "हिंदी में",
+		},
+		{
+			name:      "lang attributes",
+			page:      table(std, "12-03-2018", "A vs B", "Tax", `<p lang="en-IN">in English</p><p lang="hi">हिंदी में</p>`, pdf),
+			multiLang: true,
+			summary:   "in Englishहिंदी में", en: "in English", hi: "हिंदी में",
+		},
+		{
+			name:      "lang attributes without a header",
+			page:      `<html><body><table><tr><td>12-03-2018</td><td>A vs B</td><td>Tax</td><td><span lang="hi">हिंदी में</span></td><td>` + pdf + `</td></tr></table></body></html>`,
+			multiLang: true,
+			summary:   "हिंदी में", hi: "हिंदी में",
+		},
+		{
+			name:      "single summary",
+			page:      table(std, "12-03-2018", "A vs B", "Tax", "plain", pdf),
+			multiLang: true,
+			summary:   "plain",
+		},
+		{
+			name:    "off",
+			page:    table(std, "12-03-2018", "A vs B", "Tax", `<p lang="en">in English</p><p lang="hi">हिंदी में</p>`, pdf),
+			summary: "in Englishहिंदी में",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := &Scraper{Logger: discard, MultiLang: tc.multiLang}
+			js, _, err := sc.ParseHTML(strings.NewReader(tc.page), "https://example.org/judgments/", 2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(js) != 1 {
+				t.Fatalf("parsed %d judgments, want 1", len(js))
+			}
+			j := js[0]
+			if j.JudgmentSummary != tc.summary || j.SummaryEN != tc.en || j.SummaryHI != tc.hi {
+				t.Errorf("summary %q, en %q, hi %q; want %q, %q, %q", j.JudgmentSummary, j.SummaryEN, j.SummaryHI, tc.summary, tc.en, tc.hi)
+			}
+			if j.Subject != "Tax" || j.PDFLink != "https://example.org/a.pdf" {
+				t.Errorf("subject %q, link %q: the language columns shifted the others", j.Subject, j.PDFLink)
+			}
+		})
+	}
+}
+
+func TestMultiLangOffIgnoresLanguageColumns(t *testing.T) {
+	page := `<html><body><table><tr><th>Date of Judgment</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>English</th><th>View</th></tr>` +
+		`<tr><td>12-03-2018</td><td>A vs B</td><td>Tax</td><td>plain</td><td>in English</td><td><a href="/a.pdf">PDF</a></td></tr></table></body></html>`
+	js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 || js[0].JudgmentSummary != "plain" || js[0].SummaryEN != "" {
+		t.Errorf("parsed %+v, want the plain summary and no SummaryEN without MultiLang", js)
+	}
+}
+
+func TestMultiLangFetchedYear(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", `<p lang="en">in English</p><p lang="hi">हिंदी में</p>`, "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "plain", "/b.pdf"),
+	)}, nil)
+	sc := s.scraper()
+	sc.MultiLang = true
+	js, err := sc.FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 2 || js[0].SummaryEN != "in English" || js[0].SummaryHI != "हिंदी में" || js[1].SummaryEN != "" || js[1].SummaryHI != "" || js[1].JudgmentSummary != "plain" {
+		t.Errorf("fetched %+v, want both languages of the first summary only", js)
+	}
+}
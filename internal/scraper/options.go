@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures a Scraper built by NewScraper.
+type Option func(*Scraper)
+
+// NewScraper returns a Scraper configured by opts. Without options it
+// behaves like the zero Scraper and the package-level functions.
+func NewScraper(opts ...Option) *Scraper {
+	sc := &Scraper{}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	return sc
+}
+
+// WithHTTPClient sets the client used for every request.
+func WithHTTPClient(c *http.Client) Option {
+	return func(sc *Scraper) { sc.HTTPClient = c }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(sc *Scraper) { sc.UserAgent = ua }
+}
+
+// WithHeaders sets headers sent with every request; see
+// Scraper.RequestHeaders.
+func WithHeaders(h map[string]string) Option {
+	return func(sc *Scraper) { sc.RequestHeaders = h }
+}
+
+// WithCookies sets cookies sent with every request to the site; see
+// Scraper.Cookies.
+func WithCookies(cookies ...*http.Cookie) Option {
+	return func(sc *Scraper) { sc.Cookies = cookies }
+}
+
+// WithBaseURL sets the landmark judgments page to scrape.
+func WithBaseURL(u string) Option {
+	return func(sc *Scraper) { sc.BaseURL = u }
+}
+
+// WithRateLimit limits requests to rps per second, allowing bursts of burst
+// requests. A non-positive rps removes the limit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(sc *Scraper) {
+		if rps <= 0 {
+			sc.Limiter = nil
+			return
+		}
+		sc.Limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+	}
+}
+
+// WithMinDelay spaces requests at least d apart across everything sharing
+// the scraper's Limiter, replacing any rate limit set before. A non-positive
+// d removes the limit.
+func WithMinDelay(d time.Duration) Option {
+	return func(sc *Scraper) {
+		if d <= 0 {
+			sc.Limiter = nil
+			return
+		}
+		sc.Limiter = rate.NewLimiter(rate.Every(d), 1)
+	}
+}
+
+// WithLogger sets where progress messages go.
+func WithLogger(l Logger) Option {
+	return func(sc *Scraper) { sc.Logger = l }
+}
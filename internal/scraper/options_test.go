@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// pacedSite serves a one-judgment page for each of years.
+func pacedSite(t *testing.T, years ...int) *site {
+	t.Helper()
+	pages := map[int]string{}
+	for _, y := range years {
+		pages[y] = yearPage(row(1, "12-03-2018", fmt.Sprintf("case of %d", y), "Tax", "s", fmt.Sprintf("/%d.pdf", y)))
+	}
+	return newSite(t, pages, nil)
+}
+
+func TestNewScraper(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+	logger := &captureLogger{}
+	sc := NewScraper(
+		WithHTTPClient(client),
+		WithUserAgent("research-bot/1.0"),
+		WithBaseURL("https://example.org/judgments/"),
+		WithHeaders(map[string]string{"X-Token": "t"}),
+		WithRateLimit(2, 3),
+		WithLogger(logger),
+	)
+	if sc.HTTPClient != client || sc.UserAgent != "research-bot/1.0" || sc.BaseURL != "https://example.org/judgments/" || sc.Logger != logger {
+		t.Errorf("options not applied: %+v", sc)
+	}
+	if !reflect.DeepEqual(sc.RequestHeaders, map[string]string{"X-Token": "t"}) {
+		t.Errorf("RequestHeaders = %v", sc.RequestHeaders)
+	}
+	if sc.Limiter == nil || sc.Limiter.Limit() != 2 || sc.Limiter.Burst() != 3 {
+		t.Errorf("Limiter = %+v, want 2 per second with bursts of 3", sc.Limiter)
+	}
+
+	WithMinDelay(250 * time.Millisecond)(sc)
+	if sc.Limiter == nil || sc.Limiter.Limit() != rate.Every(250*time.Millisecond) || sc.Limiter.Burst() != 1 {
+		t.Errorf("WithMinDelay left Limiter = %+v", sc.Limiter)
+	}
+	WithRateLimit(0, 5)(sc)
+	if sc.Limiter != nil {
+		t.Error("WithRateLimit(0) kept the limiter")
+	}
+	WithRateLimit(1, 1)(sc)
+	WithMinDelay(0)(sc)
+	if sc.Limiter != nil {
+		t.Error("WithMinDelay(0) kept the limiter")
+	}
+
+	if def := NewScraper(); !reflect.DeepEqual(def, &Scraper{}) {
+		t.Errorf("NewScraper() = %+v, want the zero Scraper", def)
+	}
+	if NewScraper().userAgent() != DefaultUserAgent {
+		t.Error("default scraper does not send DefaultUserAgent")
+	}
+}
+
+func TestRateLimitAcrossWorkers(t *testing.T) {
+	years := []int{2016, 2017, 2018, 2019, 2020}
+	s := pacedSite(t, years...)
+	sc := s.scraper()
+	WithRateLimit(20, 1)(sc)
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	results, err := sc.ScrapeYearsConcurrent(context.Background(), years, t.TempDir(), len(years))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y, err := range results {
+		if err != nil {
+			t.Errorf("year %d: %v", y, err)
+		}
+	}
+	// 20 requests per second are 50ms apart; one gap can shrink as requests
+	// race to the server, so check the span of all of them
+	s.mu.Lock()
+	span := s.arrived[len(s.arrived)-1].Sub(s.arrived[0])
+	s.mu.Unlock()
+	if want := time.Duration(len(years)-1) * 50 * time.Millisecond; span < want-20*time.Millisecond {
+		t.Errorf("concurrent workers sent %d requests within %v, want at least %v", len(years), span, want)
+	}
+}
+
+func TestMinDelayAcrossWorkers(t *testing.T) {
+	years := []int{2016, 2017, 2018, 2019}
+	s := pacedSite(t, years...)
+	sc := s.scraper()
+	WithMinDelay(60 * time.Millisecond)(sc)
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	if _, err := sc.ScrapeYearsConcurrent(context.Background(), years, t.TempDir(), len(years)); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	span := s.arrived[len(s.arrived)-1].Sub(s.arrived[0])
+	s.mu.Unlock()
+	if want := time.Duration(len(years)-1) * 60 * time.Millisecond; span < want-20*time.Millisecond {
+		t.Errorf("concurrent workers sent %d requests within %v, want at least %v", len(years), span, want)
+	}
+}
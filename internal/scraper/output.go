@@ -0,0 +1,422 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Format is an output file format.
+type Format string
+
+const (
+	// FormatJSON writes an indented JSON array of judgments.
+	FormatJSON Format = "json"
+	// FormatNDJSON writes one compact JSON judgment per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes a CSV file with a header row.
+	FormatCSV Format = "csv"
+	// FormatSQLite upserts into one SQLite database shared by all years,
+	// sci_judgments.db or Scraper.DBPath. It cannot be written to an
+	// io.Writer.
+	FormatSQLite Format = "sqlite"
+)
+
+// Encode writes judgments to w in the scraper's Format.
+func (sc *Scraper) Encode(w io.Writer, judgments []Judgment) error {
+	switch sc.format() {
+	case FormatJSON:
+		return EncodeJudgments(w, judgments)
+	case FormatNDJSON:
+		return encodeNDJSON(w, judgments)
+	case FormatCSV:
+		if sc.CSVBOM {
+			if _, err := io.WriteString(w, utf8BOM); err != nil {
+				return err
+			}
+		}
+		return WriteCSV(w, judgments)
+	}
+	return fmt.Errorf("unsupported output format %q", sc.Format)
+}
+
+// WriteYear writes judgments to sci_judgments_<year>.<format> in outDir in
+// the scraper's Format, or into the DBPath database for FormatSQLite. With Gzip
+// the file is compressed and named with a further .gz, and with Checksum a
+// .sha256 sidecar is written next to it. It is the default persistence step
+// of ScrapeYear.
+func (sc *Scraper) WriteYear(outDir string, year int, judgments []Judgment) error {
+	switch sc.format() {
+	case FormatJSON, FormatNDJSON, FormatCSV:
+	case FormatSQLite:
+		if sc.Gzip {
+			return errors.New("gzip compression is not supported for sqlite output")
+		}
+		db := sc.dbPath(outDir)
+		if err := os.MkdirAll(filepath.Dir(db), 0o755); err != nil {
+			return err
+		}
+		return WriteSQLite(db, judgments)
+	default:
+		return fmt.Errorf("unsupported output format %q", sc.Format)
+	}
+	write := func(w io.Writer) error {
+		return sc.Encode(w, judgments)
+	}
+	if sc.Gzip {
+		write = gzipped(write)
+	}
+	name, err := sc.YearFile(year)
+	if err != nil {
+		return err
+	}
+	if err := writeYearFile(outDir, name, write); err != nil {
+		return err
+	}
+	if sc.Checksum {
+		return WriteChecksum(filepath.Join(outDir, name))
+	}
+	return nil
+}
+
+// YearFile returns the path, relative to the output directory, of year's
+// file in the scraper's Format: FileTemplate rendered, or YearFileName by
+// default, with .gz appended when Gzip is set.
+func (sc *Scraper) YearFile(year int) (string, error) {
+	name := YearFileName(year, sc.format())
+	if sc.FileTemplate != "" {
+		tmpl, err := template.New("file").Option("missingkey=error").Parse(sc.FileTemplate)
+		if err != nil {
+			return "", fmt.Errorf("file template: %w", err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, struct {
+			Year   int
+			Format Format
+		}{year, sc.format()}); err != nil {
+			return "", fmt.Errorf("file template: %w", err)
+		}
+		name = filepath.FromSlash(b.String())
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("file template: %q is not a path inside the output directory", name)
+		}
+	}
+	if sc.Gzip {
+		name += ".gz"
+	}
+	return name, nil
+}
+
+// gzipped wraps write so that its output is gzip-compressed. The gzip
+// stream is closed, flushing it, before the wrapped writer is released.
+func gzipped(write func(io.Writer) error) func(io.Writer) error {
+	return func(w io.Writer) error {
+		gz := gzip.NewWriter(w)
+		if err := write(gz); err != nil {
+			gz.Close()
+			return err
+		}
+		return gz.Close()
+	}
+}
+
+func (sc *Scraper) format() Format {
+	if sc.Format == "" {
+		return FormatJSON
+	}
+	return sc.Format
+}
+
+// WriteJSON writes judgments to sci_judgments_<year>.json in outDir, creating
+// the directory if needed.
+func WriteJSON(outDir string, year int, judgments []Judgment) error {
+	return writeYearFile(outDir, YearFileName(year, FormatJSON), func(w io.Writer) error {
+		return EncodeJudgments(w, judgments)
+	})
+}
+
+// EncodeJudgments writes judgments to w as an indented JSON array without
+// HTML escaping.
+func EncodeJudgments(w io.Writer, judgments []Judgment) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	// preserve characters like '&' in URLs instead of escaping to \u0026
+	enc.SetEscapeHTML(false)
+	return enc.Encode(judgments)
+}
+
+// WriteNDJSON writes judgments to sci_judgments_<year>.ndjson in outDir, one
+// compact JSON object per line, creating the directory if needed.
+func WriteNDJSON(outDir string, year int, judgments []Judgment) error {
+	return writeYearFile(outDir, YearFileName(year, FormatNDJSON), func(w io.Writer) error {
+		return encodeNDJSON(w, judgments)
+	})
+}
+
+func encodeNDJSON(w io.Writer, judgments []Judgment) error {
+	enc := json.NewEncoder(w)
+	// preserve characters like '&' in URLs instead of escaping to \u0026
+	enc.SetEscapeHTML(false)
+	for _, j := range judgments {
+		if err := enc.Encode(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergedFileName is the name of the combined file written by WriteMerged.
+const MergedFileName = "sci_judgments_all.json"
+
+// WriteMerged writes the judgments of every year, ordered by year, to
+// sci_judgments_all.json in outDir as one indented JSON array in which each
+// record carries its year, taken from the map key when Year is unset.
+func WriteMerged(outDir string, byYear map[int][]Judgment) error {
+	mw := NewMergedWriter(outDir)
+	for _, y := range slices.Sorted(maps.Keys(byYear)) {
+		if err := mw.Add(y, byYear[y]); err != nil {
+			mw.Abort()
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// MergedWriter streams judgments into sci_judgments_all.json in a directory
+// as years are scraped, laid out like WriteMerged's file but in the order
+// the years are added, so that a run need not hold every year in memory.
+// The file is built under a temporary name and replaces any earlier one
+// only when Close succeeds. It is safe for concurrent use.
+type MergedWriter struct {
+	mu     sync.Mutex
+	outDir string
+	f      *os.File
+	bw     *bufio.Writer
+	aw     *JSONArrayWriter
+	err    error
+}
+
+// NewMergedWriter returns a MergedWriter for outDir. Nothing is created
+// until the first Add or Close.
+func NewMergedWriter(outDir string) *MergedWriter {
+	return &MergedWriter{outDir: outDir}
+}
+
+// Add appends the judgments of year, setting Year where it is unset.
+func (mw *MergedWriter) Add(year int, judgments []Judgment) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	if err := mw.open(); err != nil {
+		return err
+	}
+	for _, j := range judgments {
+		if j.Year == 0 {
+			j.Year = year
+		}
+		if err := mw.aw.Write(j); err != nil {
+			return mw.fail(err)
+		}
+	}
+	return nil
+}
+
+// Close ends the array and moves the file into place. After a failed Add
+// it returns that failure and writes nothing.
+func (mw *MergedWriter) Close() error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	if err := mw.open(); err != nil {
+		return err
+	}
+	if err := mw.aw.Close(); err != nil {
+		return mw.fail(err)
+	}
+	if err := mw.bw.Flush(); err != nil {
+		return mw.fail(err)
+	}
+	tmp := mw.f.Name()
+	if err := mw.f.Close(); err != nil {
+		return mw.fail(err)
+	}
+	mw.f = nil
+	if err := os.Rename(tmp, filepath.Join(mw.outDir, MergedFileName)); err != nil {
+		os.Remove(tmp)
+		return mw.fail(err)
+	}
+	mw.err = errors.New("merged file already closed")
+	return nil
+}
+
+// Abort discards the file being written, leaving any earlier one alone.
+func (mw *MergedWriter) Abort() {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	if mw.err == nil {
+		mw.fail(errors.New("merged file aborted"))
+	}
+}
+
+// open creates the temporary file on first use and reports any earlier
+// failure.
+func (mw *MergedWriter) open() error {
+	if mw.err != nil || mw.f != nil {
+		return mw.err
+	}
+	if err := os.MkdirAll(mw.outDir, 0o755); err != nil {
+		return mw.fail(err)
+	}
+	f, err := os.CreateTemp(mw.outDir, MergedFileName+".*.tmp")
+	if err != nil {
+		return mw.fail(err)
+	}
+	mw.f = f
+	mw.bw = bufio.NewWriter(f)
+	mw.aw = NewJSONArrayWriter(mw.bw)
+	return nil
+}
+
+// fail records err as the writer's outcome, removing any temporary file,
+// and returns it.
+func (mw *MergedWriter) fail(err error) error {
+	if mw.f != nil {
+		mw.f.Close()
+		os.Remove(mw.f.Name())
+		mw.f = nil
+	}
+	mw.err = err
+	return err
+}
+
+// utf8BOM is the byte order mark Scraper.CSVBOM writes before CSV output.
+const utf8BOM = "\ufeff"
+
+// csvHeader matches the JSON field names of the core Judgment fields.
+var csvHeader = []string{"judgment_date", "cause_title_case_no", "subject", "judgment_summary", "pdf_link"}
+
+// WriteCSV writes judgments to w as CSV with a header row. Fields containing
+// commas, quotes or newlines are quoted.
+func WriteCSV(w io.Writer, judgments []Judgment) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, j := range judgments {
+		if err := cw.Write([]string{j.DateOfJudgment, j.CauseTitleCaseNo, j.Subject, j.JudgmentSummary, j.PDFLink}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeYearFile writes the file name in outDir atomically, creating the
+// directories on its path if needed.
+func writeYearFile(outDir, name string, write func(io.Writer) error) error {
+	path := filepath.Join(outDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, write)
+}
+
+// YearFileName returns the per-year file name for format,
+// sci_judgments_<year>.<format>.
+func YearFileName(year int, format Format) string {
+	return fmt.Sprintf("sci_judgments_%d.%s", year, format)
+}
+
+// OutputExists reports whether outDir already holds usable output for year
+// in the scraper's Format: a non-empty file that parses as that format, or
+// for FormatSQLite, rows for the year in the database. Empty or corrupt files
+// count as missing so that a re-run repairs them.
+func (sc *Scraper) OutputExists(outDir string, year int) bool {
+	format := sc.format()
+	if format == FormatSQLite {
+		return sqliteHasYear(sc.dbPath(outDir), year)
+	}
+	name, err := sc.YearFile(year)
+	if err != nil {
+		return false
+	}
+	data, err := sc.readYearFile(filepath.Join(outDir, name))
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return false
+	}
+	switch format {
+	case FormatJSON:
+		var js []json.RawMessage
+		return json.Unmarshal(data, &js) == nil
+	case FormatNDJSON:
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 && !json.Valid(line) {
+				return false
+			}
+		}
+		return true
+	case FormatCSV:
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+		return err == nil && len(records) > 0 && slices.Equal(records[0], csvHeader)
+	}
+	return false
+}
+
+// readYearFile reads a per-year file, decompressing it when Gzip is set.
+func (sc *Scraper) readYearFile(path string) ([]byte, error) {
+	if !sc.Gzip {
+		return os.ReadFile(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+// writeFileAtomic writes the content produced by fn to a temporary file in
+// the same directory as path and renames it over path only once fn and the
+// close have succeeded. On any error the temporary file is removed and path
+// is left untouched, so an interrupted or failed write never leaves a
+// truncated file. Each call gets its own temporary file, so concurrent
+// writes to one path cannot interleave.
+func writeFileAtomic(path string, fn func(io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer func() {
+		if err != nil {
+			os.Remove(tmp)
+		}
+	}()
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,334 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomicConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = writeFileAtomic(path, func(w io.Writer) error {
+				for range 1000 {
+					if _, err := fmt.Fprintf(w, "%d", i); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("write %d: %v", i, err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := string(data[:1]); strings.Count(string(data), first) != 1000 || len(data) != 1000 {
+		t.Errorf("file mixes concurrent writes: %.40q…", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the file", len(entries))
+	}
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	js := syntheticJudgments(20)
+	for i := range js {
+		js[i].Year = 2018
+		if i%2 == 0 {
+			js[i].ParsedDate = time.Date(2018, 1, i%28+1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	dir := t.TempDir()
+	if err := WriteNDJSON(dir, 2018, js); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "sci_judgments_2018.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != len(js) {
+		t.Fatalf("wrote %d lines for %d judgments", len(lines), len(js))
+	}
+	for i, line := range lines {
+		var j Judgment
+		if err := json.Unmarshal([]byte(line), &j); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(j, js[i]) {
+			t.Errorf("line %d decodes to %+v, want %+v", i+1, j, js[i])
+		}
+	}
+	if !strings.Contains(lines[0], "&b=2") {
+		t.Errorf("links are HTML-escaped: %s", lines[0])
+	}
+
+	back, err := (&Scraper{Format: FormatNDJSON}).ReadYear(dir, 2018)
+	if err != nil || !reflect.DeepEqual(back, js) {
+		t.Errorf("ReadYear = %d judgments, %v; want the %d written", len(back), err, len(js))
+	}
+	var buf bytes.Buffer
+	if err := (&Scraper{Format: FormatNDJSON}).Encode(&buf, nil); err != nil || buf.Len() != 0 {
+		t.Errorf("encoding no judgments wrote %q, %v", buf.String(), err)
+	}
+}
+
+func TestWriteCSVQuoting(t *testing.T) {
+	js := []Judgment{
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A, B and C vs State", Subject: `The "Tax" case`, JudgmentSummary: "line one\nline two", PDFLink: "https://example.org/a.pdf?x=1&y=2"},
+		{DateOfJudgment: "13-03-2018", CauseTitleCaseNo: "plain", Subject: "", JudgmentSummary: "", PDFLink: ""},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, js); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		csvHeader,
+		{"12-03-2018", "A, B and C vs State", `The "Tax" case`, "line one\nline two", "https://example.org/a.pdf?x=1&y=2"},
+		{"13-03-2018", "plain", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("CSV reads back as %q, want %q", records, want)
+	}
+}
+
+func TestCSVBOM(t *testing.T) {
+	js := syntheticJudgments(2)
+	for _, tc := range []struct {
+		format Format
+		bom    bool
+		want   bool
+	}{
+		{FormatCSV, true, true},
+		{FormatCSV, false, false},
+		{FormatJSON, true, false},
+	} {
+		dir := t.TempDir()
+		sc := &Scraper{Format: tc.format, CSVBOM: tc.bom}
+		if err := sc.WriteYear(dir, 2018, js); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, YearFileName(2018, tc.format)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.HasPrefix(data, []byte(utf8BOM)); got != tc.want {
+			t.Errorf("%s with CSVBOM %v: file starts with a BOM = %v, want %v", tc.format, tc.bom, got, tc.want)
+		}
+		if tc.format != FormatCSV {
+			continue
+		}
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 3 || !reflect.DeepEqual(records[0], csvHeader) {
+			t.Errorf("CSVBOM %v: file reads back as %q, want the header and 2 rows", tc.bom, records)
+		}
+	}
+}
+
+func TestEncodeMatchesWriteYear(t *testing.T) {
+	js := syntheticJudgments(3)
+	for _, f := range []Format{FormatJSON, FormatNDJSON, FormatCSV} {
+		sc := &Scraper{Format: f}
+		var buf bytes.Buffer
+		if err := sc.Encode(&buf, js); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		dir := t.TempDir()
+		if err := sc.WriteYear(dir, 2018, js); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, YearFileName(2018, f)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() == 0 || !bytes.Equal(buf.Bytes(), data) {
+			t.Errorf("%s: Encode wrote %d bytes that differ from the %d of the file", f, buf.Len(), len(data))
+		}
+	}
+	if err := (&Scraper{Format: FormatSQLite}).Encode(io.Discard, js); err == nil {
+		t.Error("encoding sqlite to a writer succeeded")
+	}
+}
+
+func TestGzipOutputReadBack(t *testing.T) {
+	js := syntheticJudgments(20)
+	for _, f := range []Format{FormatJSON, FormatNDJSON, FormatCSV} {
+		sc := &Scraper{Format: f, Gzip: true}
+		dir := t.TempDir()
+		if err := sc.WriteYear(dir, 2018, js); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		file, err := os.Open(filepath.Join(dir, YearFileName(2018, f)+".gz"))
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		plain, err := io.ReadAll(zr)
+		file.Close()
+		if err != nil {
+			t.Fatalf("%s: the gzip stream is incomplete: %v", f, err)
+		}
+		var want bytes.Buffer
+		if err := (&Scraper{Format: f}).Encode(&want, js); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plain, want.Bytes()) {
+			t.Errorf("%s: decompressed output differs from the uncompressed encoding", f)
+		}
+		if f == FormatJSON {
+			var back []Judgment
+			if err := json.Unmarshal(plain, &back); err != nil || !reflect.DeepEqual(back, js) {
+				t.Errorf("json: read back %d judgments, %v", len(back), err)
+			}
+		}
+		if !sc.OutputExists(dir, 2018) {
+			t.Errorf("%s: OutputExists does not recognize the gzipped file", f)
+		}
+	}
+	if err := (&Scraper{Format: FormatSQLite, Gzip: true}).WriteYear(t.TempDir(), 2018, js); err == nil {
+		t.Error("gzipped sqlite output succeeded")
+	}
+}
+
+func TestWriteFileAtomicFailureLeavesFileUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	failing := func(w io.Writer) error {
+		io.WriteString(w, `[{"half":`)
+		return errors.New("encode failed")
+	}
+	for _, tc := range []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"plain", failing},
+		{"gzipped", gzipped(failing)},
+	} {
+		for _, existing := range []bool{false, true} {
+			os.Remove(path)
+			if existing {
+				if err := os.WriteFile(path, []byte("previous"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writeFileAtomic(path, tc.write); err == nil {
+				t.Fatalf("%s: a failed encode was reported as written", tc.name)
+			}
+			data, err := os.ReadFile(path)
+			switch {
+			case existing && string(data) != "previous":
+				t.Errorf("%s: the earlier file now holds %q", tc.name, data)
+			case !existing && !os.IsNotExist(err):
+				t.Errorf("%s: a failed encode created the file: %v", tc.name, err)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) > 1 {
+				t.Errorf("%s: temporary files left behind: %d entries", tc.name, len(entries))
+			}
+		}
+	}
+}
+
+func TestOutputExists(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		sc      *Scraper
+		content string
+		want    bool
+	}{
+		{"json", &Scraper{}, "[]\n", true},
+		{"zero-byte json", &Scraper{}, "", false},
+		{"truncated json", &Scraper{}, `[{"judgment_date":`, false},
+		{"ndjson", &Scraper{Format: FormatNDJSON}, "{}\n{}\n", true},
+		{"corrupt ndjson", &Scraper{Format: FormatNDJSON}, "{}\n{\n", false},
+		{"csv", &Scraper{Format: FormatCSV}, strings.Join(csvHeader, ",") + "\n", true},
+		{"csv with a bom", &Scraper{Format: FormatCSV}, utf8BOM + strings.Join(csvHeader, ",") + "\n", true},
+		{"csv of another shape", &Scraper{Format: FormatCSV}, "a,b\n", false},
+		{"whitespace", &Scraper{}, " \n", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.sc.OutputExists(dir, 2018) {
+				t.Fatal("an absent file counts as existing")
+			}
+			name, _ := tc.sc.YearFile(2018)
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.sc.OutputExists(dir, 2018); got != tc.want {
+				t.Errorf("OutputExists = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFileTemplate(t *testing.T) {
+	js := syntheticJudgments(2)
+	for _, tc := range []struct {
+		tmpl   string
+		format Format
+		gzip   bool
+		want   string
+	}{
+		{"", FormatJSON, false, "sci_judgments_2018.json"},
+		{"{{.Year}}/sci.{{.Format}}", FormatJSON, false, filepath.Join("2018", "sci.json")},
+		{"sci-{{.Year}}-landmark.{{.Format}}", FormatCSV, true, "sci-2018-landmark.csv.gz"},
+		{"archive/{{.Format}}/{{.Year}}/judgments.{{.Format}}", FormatNDJSON, false, filepath.Join("archive", "ndjson", "2018", "judgments.ndjson")},
+	} {
+		sc := &Scraper{Format: tc.format, Gzip: tc.gzip, FileTemplate: tc.tmpl}
+		name, err := sc.YearFile(2018)
+		if err != nil || name != tc.want {
+			t.Errorf("YearFile with %q = %q, %v; want %q", tc.tmpl, name, err, tc.want)
+			continue
+		}
+		dir := t.TempDir()
+		if err := sc.WriteYear(dir, 2018, js); err != nil {
+			t.Fatalf("%q: %v", tc.tmpl, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, tc.want)); err != nil {
+			t.Errorf("%q: %v", tc.tmpl, err)
+		}
+		if !sc.OutputExists(dir, 2018) {
+			t.Errorf("%q: OutputExists does not find the templated file", tc.tmpl)
+		}
+	}
+
+	for _, tmpl := range []string{"../{{.Year}}.json", "/tmp/{{.Year}}.json", "{{.Yaer}}.json", "{{.Year"} {
+		if _, err := (&Scraper{FileTemplate: tmpl}).YearFile(2018); err == nil {
+			t.Errorf("YearFile with %q succeeded", tmpl)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxPages bounds the result pages followed for one year, against a site
+// whose next links never run out.
+const maxPages = 100
+
+// nextLinkSelectors find a result page's link to the following page, most
+// specific first: rel=next, then the pagination classes used by DataTables
+// and WordPress.
+var nextLinkSelectors = []string{
+	`link[rel="next"]`,
+	`a[rel~="next"]`,
+	`.pagination a.next`,
+	`a.next.page-numbers`,
+	`.paginate_button.next a`,
+	`a.paginate_button.next`,
+}
+
+// nextLinkTexts are the texts of anchors that lead to the following page,
+// lowercased.
+var nextLinkTexts = []string{"next", "next page", "next »", "»", "›", "next ›", "next >", ">"}
+
+// nextPageURL returns the absolute URL of the page after doc, resolved
+// against base, or "" when doc has no usable next link. Links that go
+// nowhere, such as "#" and javascript: ones driven by script, are ignored.
+func nextPageURL(doc *goquery.Document, base *url.URL) string {
+	for _, sel := range nextLinkSelectors {
+		if u := firstLink(doc.Find(sel), base); u != "" {
+			return u
+		}
+	}
+	return firstLink(doc.Find("a[href]").FilterFunction(func(_ int, a *goquery.Selection) bool {
+		return slices.Contains(nextLinkTexts, strings.ToLower(strings.Join(strings.Fields(a.Text()), " ")))
+	}), base)
+}
+
+// firstLink returns the first usable href in sel resolved against base.
+func firstLink(sel *goquery.Selection, base *url.URL) string {
+	var link string
+	sel.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return true
+		}
+		if s.HasClass("disabled") || s.Parent().HasClass("disabled") {
+			return true
+		}
+		u, err := base.Parse(href)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return true
+		}
+		u.Fragment = ""
+		link = u.String()
+		return false
+	})
+	return link
+}
+
+// pageNumberSelectors find the numbered links of a pagination bar, as used
+// by WordPress and DataTables.
+var pageNumberSelectors = []string{
+	`.pagination a`,
+	`a.page-numbers`,
+	`.paginate_button a`,
+	`a.paginate_button`,
+}
+
+// listedPageURLs returns the absolute URLs of the numbered pages doc's
+// pagination bar links to, resolved against base, in page order. A bar
+// that elides pages ("1 2 … 9") lists only some of them.
+func listedPageURLs(doc *goquery.Document, base *url.URL) []string {
+	type listed struct {
+		n    int
+		link string
+	}
+	var pages []listed
+	seen := map[string]bool{}
+	for _, sel := range pageNumberSelectors {
+		doc.Find(sel).Each(func(_ int, a *goquery.Selection) {
+			n, err := strconv.Atoi(strings.TrimSpace(a.Text()))
+			if err != nil {
+				return
+			}
+			if link := firstLink(a, base); link != "" && !seen[link] {
+				seen[link] = true
+				pages = append(pages, listed{n, link})
+			}
+		})
+	}
+	slices.SortStableFunc(pages, func(a, b listed) int { return cmp.Compare(a.n, b.n) })
+	links := make([]string, len(pages))
+	for i, p := range pages {
+		links[i] = p.link
+	}
+	return links
+}
+
+// prefetch is a set of a year's result pages being fetched ahead of the
+// parse, which takes them as its next links reach them.
+type prefetch struct {
+	pages  map[string]*prefetched
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// prefetched is a page of a prefetch; done is closed once pg or err is set.
+type prefetched struct {
+	link string
+	done chan struct{}
+	pg   *page
+	err  error
+}
+
+// prefetchPages starts fetching links for year, workers at a time and in
+// the order given, skipping those in skip. Call stop once the parse ends.
+func (sc *Scraper) prefetchPages(ctx context.Context, year int, links []string, skip map[string]bool, workers int) *prefetch {
+	ctx, cancel := context.WithCancel(ctx)
+	pf := &prefetch{pages: map[string]*prefetched{}, cancel: cancel}
+	queue := make(chan *prefetched, len(links))
+	for _, link := range links {
+		if skip[link] || pf.pages[link] != nil {
+			continue
+		}
+		p := &prefetched{link: link, done: make(chan struct{})}
+		pf.pages[link] = p
+		queue <- p
+	}
+	close(queue)
+	sc.debugf("year %d: fetching %d listed pages, %d at a time", year, len(pf.pages), workers)
+	for range min(workers, len(pf.pages)) {
+		pf.wg.Add(1)
+		go func() {
+			defer pf.wg.Done()
+			for p := range queue {
+				p.pg, p.err = sc.loadPage(ctx, year, p.link)
+				close(p.done)
+			}
+		}()
+	}
+	return pf
+}
+
+// take waits for link if it is being prefetched and returns it, with its
+// page or error set, or returns nil when it is not.
+func (pf *prefetch) take(link string) *prefetched {
+	if pf == nil || pf.pages[link] == nil {
+		return nil
+	}
+	p := pf.pages[link]
+	<-p.done
+	return p
+}
+
+// stop cancels the pages not yet fetched and waits for the workers.
+func (pf *prefetch) stop() {
+	if pf == nil {
+		return
+	}
+	pf.cancel()
+	pf.wg.Wait()
+}
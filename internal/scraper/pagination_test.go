@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestNextPageURL(t *testing.T) {
+	base, _ := url.Parse("https://example.org/judgments/?judgment_year=2018")
+	for _, tc := range []struct {
+		name, html, want string
+	}{
+		{"rel next link", `<link rel="next" href="/p2">`, "https://example.org/p2"},
+		{"rel next anchor", `<a rel="nofollow next" href="?page=2">2</a>`, "https://example.org/judgments/?page=2"},
+		{"wordpress", `<a class="next page-numbers" href="/page/2/">2</a>`, "https://example.org/page/2/"},
+		{"datatables", `<li class="paginate_button next"><a href="p2">Next</a></li>`, "https://example.org/judgments/p2"},
+		{"next text", `<a href="/p2">  Next  </a>`, "https://example.org/p2"},
+		{"arrow text", `<a href="/p2">&raquo;</a>`, "https://example.org/p2"},
+		{"fragment dropped", `<link rel="next" href="/p2#top">`, "https://example.org/p2"},
+		{"selector before text", `<a href="/by-text">Next</a><link rel="next" href="/by-rel">`, "https://example.org/by-rel"},
+		{"hash link", `<a href="#">Next</a>`, ""},
+		{"script link", `<a href="javascript:next()">Next</a>`, ""},
+		{"disabled", `<li class="paginate_button next disabled"><a href="/p2">Next</a></li>`, ""},
+		{"not http", `<a href="mailto:a@example.org">Next</a>`, ""},
+		{"none", `<a href="/about">About</a>`, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head></head><body>" + tc.html + "</body></html>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nextPageURL(doc, base); got != tc.want {
+				t.Errorf("nextPageURL = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPaginationFollowsPages(t *testing.T) {
+	page := func(next string, rows ...string) string {
+		return `<html><head><link rel="next" href="` + next + `"></head><body><table>` + tableHeader + strings.Join(rows, "") + `</table></body></html>`
+	}
+	s := newSite(t, map[int]string{2018: page("/p2",
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf"),
+	)}, map[string]string{
+		"/p2": page("/p3", row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf")),
+		// the last page links back to the first
+		"/p3": page("/judgments/?judgment_year=2018", row(3, "14-03-2018", "E vs F", "Tax", "s3", "/c.pdf")),
+	})
+	js, err := s.scraper().FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, j := range js {
+		got = append(got, j.JudgmentSummary)
+	}
+	if want := []string{"s1", "s2", "s3"}; !slices.Equal(got, want) {
+		t.Errorf("collected summaries %q across the pages, want %q", got, want)
+	}
+	for _, p := range []string{"/judgments/", "/p2", "/p3"} {
+		if n := s.hits(p); n != 1 {
+			t.Errorf("%s fetched %d times, want 1", p, n)
+		}
+	}
+}
+
+func TestPaginationStopsAtMaxPages(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		fmt.Fprintf(w, `<html><head><link rel="next" href="/p%d"></head><body><table>%s%s</table></body></html>`,
+			n+1, tableHeader, row(int(n), "12-03-2018", fmt.Sprintf("P%d vs State", n), "Tax", "s", fmt.Sprintf("/%d.pdf", n)))
+	}))
+	defer srv.Close()
+	logger := &captureLogger{}
+	sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: logger}
+	js, err := sc.FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != maxPages {
+		t.Errorf("fetched %d pages, want maxPages %d", n, maxPages)
+	}
+	if len(js) != maxPages {
+		t.Errorf("collected %d judgments, want %d", len(js), maxPages)
+	}
+	if !logger.contains(fmt.Sprintf("stopped after %d pages", maxPages)) {
+		t.Errorf("logged %q, want a warning about the page cap", logger.lines)
+	}
+}
+
+func TestPageConcurrency(t *testing.T) {
+	bar := `<div class="pagination"><a href="/p2">2</a><a href="/p3">3</a><a href="/p4">4</a><a href="/p2">Next</a></div>`
+	page := func(n int) string {
+		next := ""
+		if n < 4 {
+			next = fmt.Sprintf(`<link rel="next" href="/p%d">`, n+1)
+		}
+		return fmt.Sprintf(`<html><head>%s</head><body>%s<table>%s%s%s</table></body></html>`, next, bar, tableHeader,
+			row(2*n-1, "12-03-2018", fmt.Sprintf("P%d vs State", 2*n-1), "Tax", fmt.Sprintf("s%d", 2*n-1), fmt.Sprintf("/%d.pdf", 2*n-1)),
+			row(2*n, "12-03-2018", fmt.Sprintf("P%d vs State", 2*n), "Tax", fmt.Sprintf("s%d", 2*n), fmt.Sprintf("/%d.pdf", 2*n)))
+	}
+	var inFlight, peak atomic.Int32
+	hits := map[string]*atomic.Int32{"/judgments/": {}, "/p2": {}, "/p3": {}, "/p4": {}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		hits[r.URL.Path].Add(1)
+		// later pages answer first, so a merge in arrival order would show
+		num := map[string]int{"/judgments/": 1, "/p2": 2, "/p3": 3, "/p4": 4}[r.URL.Path]
+		if num > 1 {
+			time.Sleep(time.Duration(5-num) * 30 * time.Millisecond)
+		}
+		io.WriteString(w, page(num))
+	}))
+	defer srv.Close()
+	sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: discard, PageConcurrency: 3}
+	js, err := sc.FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, j := range js {
+		got = append(got, j.JudgmentSummary)
+	}
+	if want := []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8"}; !slices.Equal(got, want) {
+		t.Errorf("collected summaries %q, want %q in page order", got, want)
+	}
+	for p, n := range hits {
+		if n.Load() != 1 {
+			t.Errorf("%s fetched %d times, want 1", p, n.Load())
+		}
+	}
+	if p := peak.Load(); p < 2 || p > 3 {
+		t.Errorf("peak of %d pages in flight, want 2 or 3 with PageConcurrency 3", p)
+	}
+}
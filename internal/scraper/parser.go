@@ -0,0 +1,102 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Parser reads the judgments from a year's listing page in one generation
+// of the site's markup, resolving relative links against base. Scraper sets
+// Year and, unless set, ParsedDate on the judgments returned, then applies
+// its filters and OnJudgment as for the built-in parser.
+type Parser interface {
+	Parse(doc *goquery.Document, base *url.URL) ([]Judgment, error)
+}
+
+// DefaultParser is the name of the built-in parser for the current site
+// layout, used when no registered parser detects a page.
+const DefaultParser = "v2025"
+
+// registeredParser is a Parser with the function recognizing its pages.
+type registeredParser struct {
+	parser Parser
+	detect func(*goquery.Document) bool
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]registeredParser{DefaultParser: {parser: builtinParser{}}}
+	// parserOrder lists registered names in registration order, the order
+	// auto-detection tries them in
+	parserOrder []string
+)
+
+// RegisterParser makes p available under name, for Scraper.Parser and
+// auto-detection. detect, if not nil, reports whether a page is in p's
+// markup; pages no registered parser detects go to DefaultParser. It panics
+// if name is already registered or p is nil.
+func RegisterParser(name string, p Parser, detect func(doc *goquery.Document) bool) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	if p == nil {
+		panic("scraper: RegisterParser parser is nil")
+	}
+	if _, dup := parsers[name]; dup {
+		panic("scraper: RegisterParser called twice for parser " + name)
+	}
+	parsers[name] = registeredParser{parser: p, detect: detect}
+	parserOrder = append(parserOrder, name)
+}
+
+// Parsers returns the names of the registered parsers, sorted.
+func Parsers() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// selectParser returns the parser named by sc.Parser, or the first
+// registered one detecting doc, with its name. A nil Parser means the
+// built-in one.
+func (sc *Scraper) selectParser(doc *goquery.Document) (Parser, string, error) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	name := sc.Parser
+	if name == "" {
+		name = DefaultParser
+		for _, n := range parserOrder {
+			if detect := parsers[n].detect; detect != nil && detect(doc) {
+				name = n
+				break
+			}
+		}
+	}
+	reg, ok := parsers[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown parser %q", name)
+	}
+	if _, builtin := reg.parser.(builtinParser); builtin {
+		return nil, name, nil
+	}
+	return reg.parser, name, nil
+}
+
+// builtinParser is DefaultParser: the table parsing of parseDocument with
+// the zero Scraper's options.
+type builtinParser struct{}
+
+func (builtinParser) Parse(doc *goquery.Document, base *url.URL) ([]Judgment, error) {
+	sc := &Scraper{Parser: DefaultParser, LogLevel: LogQuiet}
+	judgments, _, err := sc.parseDocument(context.Background(), doc, base, 0, "")
+	return judgments, err
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// listParser is a fake parser for a list-based layout: one <li> per
+// judgment holding the date, a title link to the PDF, and the summary.
+type listParser struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *listParser) Parse(doc *goquery.Document, base *url.URL) ([]Judgment, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	var js []Judgment
+	doc.Find("ul.judgments li").Each(func(_ int, li *goquery.Selection) {
+		a := li.Find("a")
+		href, _ := url.Parse(a.AttrOr("href", ""))
+		js = append(js, Judgment{
+			DateOfJudgment:   li.Find(".date").Text(),
+			CauseTitleCaseNo: a.Text(),
+			JudgmentSummary:  li.Find(".summary").Text(),
+			PDFLink:          base.ResolveReference(href).String(),
+		})
+	})
+	if len(js) == 0 {
+		return nil, errors.New("no list items")
+	}
+	return js, nil
+}
+
+var (
+	registerListParser sync.Once
+	fakeParser         = &listParser{}
+)
+
+const listPage = `<html><body><ul class="judgments">
+<li><span class="date">12-03-2018</span> <a href="/a.pdf">A vs B</a> <span class="summary">held</span></li>
+<li><span class="date">13-03-2018</span> <a href="/b.pdf">C vs D</a> <span class="summary">dismissed</span></li>
+</ul></body></html>`
+
+func TestRegisteredParserSelection(t *testing.T) {
+	registerListParser.Do(func() {
+		RegisterParser("test-list", fakeParser, func(doc *goquery.Document) bool {
+			return doc.Find("ul.judgments").Length() > 0
+		})
+	})
+	if names := Parsers(); !slices.Contains(names, "test-list") || !slices.Contains(names, DefaultParser) {
+		t.Errorf("Parsers() = %v, want the default and the fake", names)
+	}
+	calls := func() int {
+		fakeParser.mu.Lock()
+		defer fakeParser.mu.Unlock()
+		return fakeParser.calls
+	}
+	before := calls()
+
+	js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(listPage), "https://example.org/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls() != before+1 {
+		t.Error("a page in the fake's layout was not detected")
+	}
+	if len(js) != 2 || js[1].CauseTitleCaseNo != "C vs D" || js[1].PDFLink != "https://example.org/b.pdf" || js[1].Year != 2018 || js[1].ParsedDate.IsZero() {
+		t.Errorf("judgments = %+v, want the fake's with Year and ParsedDate set", js)
+	}
+
+	table := yearPage(row(1, "12-03-2018", "E vs F", "Tax", "s", "/e.pdf"))
+	if js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(table), "https://example.org/", 2018); err != nil || len(js) != 1 || calls() != before+1 {
+		t.Errorf("a table page went to the fake (%d calls) or failed: %v", calls()-before, err)
+	}
+
+	forced := &Scraper{Logger: discard, Parser: "test-list"}
+	if _, _, err := forced.ParseHTML(strings.NewReader(table), "https://example.org/", 2018); err == nil || !strings.Contains(err.Error(), "test-list parser") {
+		t.Errorf("forcing the fake on a table page: err = %v, want its error", err)
+	}
+	if js, _, err := (&Scraper{Logger: discard, Parser: DefaultParser}).ParseHTML(strings.NewReader(listPage), "https://example.org/", 2018); !errors.Is(err, ErrNoJudgments) {
+		t.Errorf("forcing the default parser on a list page: %d judgments, %v", len(js), err)
+	}
+	if _, _, err := (&Scraper{Parser: "v1999"}).ParseHTML(strings.NewReader(table), "https://example.org/", 2018); err == nil {
+		t.Error("an unknown parser name was accepted")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice did not panic")
+		}
+	}()
+	RegisterParser("test-list", fakeParser, nil)
+}
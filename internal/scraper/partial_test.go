@@ -0,0 +1,165 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeepPartial(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") +
+		row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf") + `</table></body></html>`
+	for _, keep := range []bool{true, false} {
+		ctx, cancel := context.WithCancel(context.Background())
+		aborted := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/p2" {
+				// shut down while the second page is in flight
+				cancel()
+				select {
+				case <-r.Context().Done():
+					close(aborted)
+				case <-time.After(5 * time.Second):
+				}
+				return
+			}
+			io.WriteString(w, page1)
+		}))
+		var persisted []Judgment
+		sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: discard, KeepPartial: keep}
+		sc.PersistFunc = func(_ int, js []Judgment) error {
+			persisted = js
+			return nil
+		}
+		err := sc.ScrapeYearWithContext(ctx, 2018, t.TempDir())
+		srv.Close()
+		cancel()
+
+		select {
+		case <-aborted:
+		default:
+			t.Errorf("KeepPartial %v: the in-flight request was not canceled", keep)
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("KeepPartial %v: error %v does not wrap context.Canceled", keep, err)
+		}
+		if got := errors.Is(err, ErrPartial); got != keep {
+			t.Errorf("KeepPartial %v: error %v wraps ErrPartial = %v", keep, err, got)
+		}
+		if want := map[bool]int{true: 2, false: 0}[keep]; len(persisted) != want {
+			t.Errorf("KeepPartial %v: persisted %d judgments, want %d", keep, len(persisted), want)
+		}
+	}
+}
+
+func TestKeepPartialNothingGathered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := newSite(t, map[int]string{2018: yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))}, nil)
+	sc := s.scraper()
+	sc.KeepPartial = true
+	persisted := false
+	sc.PersistFunc = func(int, []Judgment) error {
+		persisted = true
+		return nil
+	}
+	err := sc.ScrapeYearWithContext(ctx, 2018, t.TempDir())
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrPartial) {
+		t.Errorf("a year canceled before any page = %v, want context.Canceled without ErrPartial", err)
+	}
+	if persisted {
+		t.Error("a year with nothing gathered was persisted")
+	}
+}
+
+func TestPageRetries(t *testing.T) {
+	page := func(next string, rows ...string) string {
+		return `<html><head><link rel="next" href="` + next + `"></head><body><table>` + tableHeader + strings.Join(rows, "") + `</table></body></html>`
+	}
+	for _, tc := range []struct {
+		name     string
+		failures int32 // of /p2
+		want     []string
+		partial  bool
+	}{
+		{"transient", 1, []string{"s1", "s2", "s3"}, false},
+		{"persistent", 100, []string{"s1"}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var p2 atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/p2":
+					if p2.Add(1) <= tc.failures {
+						http.Error(w, "busy", http.StatusServiceUnavailable)
+						return
+					}
+					io.WriteString(w, page("/p3", row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf")))
+				case "/p3":
+					io.WriteString(w, page("", row(3, "14-03-2018", "E vs F", "Tax", "s3", "/c.pdf")))
+				default:
+					io.WriteString(w, page("/p2", row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf")))
+				}
+			}))
+			defer srv.Close()
+			logger := &captureLogger{}
+			sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: logger, PageRetries: 2}
+			var persisted []string
+			sc.PersistFunc = func(_ int, js []Judgment) error {
+				for _, j := range js {
+					persisted = append(persisted, j.JudgmentSummary)
+				}
+				return nil
+			}
+			err := sc.ScrapeYear(2018, t.TempDir())
+			if got := errors.Is(err, ErrPartial); got != tc.partial {
+				t.Errorf("error %v wraps ErrPartial = %v, want %v", err, got, tc.partial)
+			}
+			if !slices.Equal(persisted, tc.want) {
+				t.Errorf("persisted %q, want %q", persisted, tc.want)
+			}
+			if want := min(tc.failures+1, 1+int32(sc.PageRetries)); p2.Load() != want {
+				t.Errorf("/p2 requested %d times, want %d", p2.Load(), want)
+			}
+
+			_, stats, err := sc.FetchYearWithStats(2018)
+			if want := map[bool]int{true: 2}[tc.partial]; stats.FailedPage != want {
+				t.Errorf("Stats.FailedPage = %d, want %d", stats.FailedPage, want)
+			}
+			if tc.partial {
+				var fe *FetchError
+				if !errors.As(err, &fe) || fe.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("partial error %v does not carry the page's 503", err)
+				}
+				if !logger.contains("page 2 failed") {
+					t.Errorf("logged %q, want a partial-year warning", logger.lines)
+				}
+			}
+		})
+	}
+}
+
+func TestPageRetriesFirstPageOnly(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	sc := &Scraper{BaseURL: srv.URL + "/judgments/", HTTPClient: srv.Client(), Logger: discard, PageRetries: 2}
+	if _, err := sc.FetchYear(2018); err == nil || errors.Is(err, ErrPartial) {
+		t.Errorf("a failing first page = %v, want a plain error", err)
+	}
+	// the first page is the year's, retried by Retries instead
+	if n := requests.Load(); n != 1 {
+		t.Errorf("the first page was requested %d times, want 1", n)
+	}
+}
@@ -0,0 +1,39 @@
+package scraper
+
+import "regexp"
+
+// PreprocessFunc rewrites raw page HTML before it is parsed.
+type PreprocessFunc func([]byte) []byte
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// the markers opening and closing conditional comments, hidden
+	// (<!--[if IE]> ... <![endif]-->) and revealed (<!--[if !IE]><!--> ...
+	// <!--<![endif]-->, or <![if !IE]> ... <![endif]>)
+	conditionalMarker = regexp.MustCompile(`(?i)<!--\[if[^\]]*\]>(?:<!-->)?|(?:<!--)?<!\[endif\]-->|<!\[if[^\]]*\]>|<!\[endif\]>`)
+	formTag           = regexp.MustCompile(`(?i)</?form\b[^>]*>`)
+)
+
+// StripComments removes HTML comments. Conditional comments lose only their
+// markers and keep the markup between them, so that one left unbalanced, an
+// opening marker whose "<![endif]-->" never comes, cannot swallow the table
+// after it.
+func StripComments(html []byte) []byte {
+	return htmlComment.ReplaceAll(conditionalMarker.ReplaceAll(html, nil), nil)
+}
+
+// StripForms removes <form> tags while keeping their contents. Forms opened
+// inside a table make the HTML parser move rows out of the table.
+func StripForms(html []byte) []byte {
+	return formTag.ReplaceAll(html, nil)
+}
+
+// ChainPreprocess returns a PreprocessFunc applying fns in order.
+func ChainPreprocess(fns ...PreprocessFunc) PreprocessFunc {
+	return func(html []byte) []byte {
+		for _, fn := range fns {
+			html = fn(html)
+		}
+		return html
+	}
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStripComments(t *testing.T) {
+	for _, tc := range []struct {
+		name, in, want string
+	}{
+		{"comment", `<td>a</td><!-- note --><td>b</td>`, `<td>a</td><td>b</td>`},
+		{"multiline", "<tr><!--\n<td>old</td>\n--><td>new</td></tr>", `<tr><td>new</td></tr>`},
+		{"several", `<!--a--><td>x</td><!--b-->`, `<td>x</td>`},
+		{"conditional", `<!--[if IE]><tr><td>a</td></tr><![endif]-->`, `<tr><td>a</td></tr>`},
+		{"revealed", `<!--[if !IE]><!--><tr><td>a</td></tr><!--<![endif]-->`, `<tr><td>a</td></tr>`},
+		{"downlevel revealed", `<![if !IE]><tr><td>a</td></tr><![endif]>`, `<tr><td>a</td></tr>`},
+		{"case", `<!--[IF lt IE 9]><td>a</td><![ENDIF]-->`, `<td>a</td>`},
+		{"unbalanced opener", `<!--[if IE]><link rel="x"><table><tr><td>a</td></tr></table>`, `<link rel="x"><table><tr><td>a</td></tr></table>`},
+		{"unbalanced closer", `<table><tr><td>a</td></tr><![endif]--></table>`, `<table><tr><td>a</td></tr></table>`},
+		{"unterminated", `<td>a</td><!-- open`, `<td>a</td><!-- open`},
+		{"none", `<td>a</td>`, `<td>a</td>`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(StripComments([]byte(tc.in))); got != tc.want {
+				t.Errorf("StripComments(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStripForms(t *testing.T) {
+	in := `<table><FORM action="/s" method=post><tr><td><input name="q"></td></tr></form><form/></table><formula>`
+	want := `<table><tr><td><input name="q"></td></tr></table><formula>`
+	if got := string(StripForms([]byte(in))); got != want {
+		t.Errorf("StripForms = %q, want %q", got, want)
+	}
+}
+
+func TestChainPreprocess(t *testing.T) {
+	in := []byte(`<form><!--[if IE]><td>a</td><![endif]--></form>`)
+	if got := string(ChainPreprocess(StripComments, StripForms)(in)); got != `<td>a</td>` {
+		t.Errorf("ChainPreprocess(StripComments, StripForms) = %q", got)
+	}
+	// fns run in order, each on the previous one's output
+	var order []string
+	step := func(name string) PreprocessFunc {
+		return func(html []byte) []byte {
+			order = append(order, name)
+			return append(bytes.Clone(html), name...)
+		}
+	}
+	if got := string(ChainPreprocess(step("1"), step("2"), step("3"))([]byte("x"))); got != "x123" || strings.Join(order, "") != "123" {
+		t.Errorf("the chain returned %q after running %q, want x123 after 123", got, order)
+	}
+	if got := string(ChainPreprocess()(in)); got != string(in) {
+		t.Errorf("an empty chain changed the page to %q", got)
+	}
+}
+
+func TestStripCommentsParsesConditionalRows(t *testing.T) {
+	page := yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") +
+			`<!--[if IE]>` + row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf"),
+	)
+	sc := &Scraper{Logger: discard}
+	js, _, err := sc.ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+	if err != nil || len(js) != 1 {
+		t.Fatalf("without StripComments parsed %d judgments, %v; want the unbalanced comment to hide the second", len(js), err)
+	}
+	sc.Preprocess = StripComments
+	if js, _, err = sc.ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018); err != nil || len(js) != 2 {
+		t.Errorf("with StripComments parsed %d judgments, %v; want 2", len(js), err)
+	}
+}
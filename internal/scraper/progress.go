@@ -0,0 +1,34 @@
+package scraper
+
+// Phase is a step of scraping one year, reported in ProgressEvents.
+type Phase string
+
+const (
+	PhaseFetching Phase = "fetching"
+	PhaseParsing  Phase = "parsing"
+	PhaseWriting  Phase = "writing"
+	PhaseDone     Phase = "done"
+	PhaseError    Phase = "error"
+)
+
+// ProgressEvent reports that a year entered a phase. Judgments is the
+// number of judgments kept so far, set from PhaseWriting on; Err is set for
+// PhaseError.
+type ProgressEvent struct {
+	Year      int
+	Phase     Phase
+	Judgments int
+	Err       error
+}
+
+// progress sends ev on Progress without blocking: events a slow consumer
+// isn't ready for are dropped.
+func (sc *Scraper) progress(ev ProgressEvent) {
+	if sc.Progress == nil {
+		return
+	}
+	select {
+	case sc.Progress <- ev:
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProgressEvents(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf"),
+	)}, nil)
+	events := make(chan ProgressEvent, 16)
+	sc := s.scraper()
+	sc.Progress = events
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.ScrapeYear(2019, t.TempDir()); err == nil {
+		t.Fatal("scraping a missing year succeeded")
+	}
+	close(events)
+	var got []ProgressEvent
+	for ev := range events {
+		if ev.Err != nil {
+			if !errors.As(ev.Err, new(*FetchError)) {
+				t.Errorf("error event carries %v, want the fetch error", ev.Err)
+			}
+			ev.Err = nil
+		}
+		got = append(got, ev)
+	}
+	want := []ProgressEvent{
+		{Year: 2018, Phase: PhaseFetching},
+		{Year: 2018, Phase: PhaseParsing},
+		{Year: 2018, Phase: PhaseWriting, Judgments: 2},
+		{Year: 2018, Phase: PhaseDone, Judgments: 2},
+		{Year: 2019, Phase: PhaseFetching},
+		{Year: 2019, Phase: PhaseError},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestProgressDoesNotBlock(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"))}, nil)
+	sc := s.scraper()
+	// nobody reads the channel
+	sc.Progress = make(chan ProgressEvent)
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+}
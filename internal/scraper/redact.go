@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// partySeparator matches the "vs" between party names in a cause title.
+var partySeparator = regexp.MustCompile(`(?i)\s+(?:vs\.?|v\.|versus)\s+`)
+
+// RedactCauseTitle replaces the party names around the "vs"/"v." separator of
+// a cause title with replacement, keeping any case number that precedes the
+// petitioner (up to a ',' or ' - ') or follows the respondent (from the first
+// ',', '(' or '['). A prefix is kept only when it consists of case numbers,
+// so that a comma between petitioners does not leak the first of them.
+// Titles without a separator are returned unchanged.
+func RedactCauseTitle(title, replacement string) string {
+	loc := partySeparator.FindStringIndex(title)
+	if loc == nil {
+		return title
+	}
+	left, sep, right := title[:loc[0]], title[loc[0]:loc[1]], title[loc[1]:]
+
+	prefix := ""
+	for _, delim := range []string{", ", " - "} {
+		for off := 0; ; {
+			i := strings.Index(left[off:], delim)
+			if i < 0 {
+				break
+			}
+			i += off
+			if end := i + len(delim); end > len(prefix) && isCaseReference(left[:i]) {
+				prefix = left[:end]
+			}
+			off = i + 1
+		}
+	}
+	suffix := ""
+	if i := strings.IndexAny(right, ",(["); i >= 0 {
+		suffix = right[len(strings.TrimRight(right[:i], " ")):]
+	}
+	return prefix + replacement + sep + replacement + suffix
+}
+
+// isCaseReference reports whether s holds one or more case numbers matched
+// by caseNumberPatterns and nothing else but punctuation.
+func isCaseReference(s string) bool {
+	found := false
+	for _, re := range caseNumberPatterns {
+		if re.MatchString(s) {
+			found = true
+			s = re.ReplaceAllString(s, " ")
+		}
+	}
+	return found && !strings.ContainsFunc(s, unicode.IsLetter)
+}
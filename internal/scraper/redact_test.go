@@ -0,0 +1,26 @@
+package scraper
+
+import "testing"
+
+func TestRedactCauseTitle(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"A vs B", "[R] vs [R]"},
+		{"A v. B", "[R] v. [R]"},
+		{"No separator here", "No separator here"},
+		{"Civil Appeal No. 12 of 2018, Rajesh vs State", "Civil Appeal No. 12 of 2018, [R] vs [R]"},
+		{"Civil Appeal No. 12 of 2018 - Rajesh vs State", "Civil Appeal No. 12 of 2018 - [R] vs [R]"},
+		{"Civil Appeal No. 1 of 2018, Civil Appeal No. 2 of 2018 - Rajesh vs State", "Civil Appeal No. 1 of 2018, Civil Appeal No. 2 of 2018 - [R] vs [R]"},
+		{"Rajesh vs State of Punjab, Civil Appeal No. 12 of 2018", "[R] vs [R], Civil Appeal No. 12 of 2018"},
+		{"Rajesh Kumar, Suresh Kumar vs State of Punjab, Civil Appeal No. 12 of 2018", "[R] vs [R], Civil Appeal No. 12 of 2018"},
+		{"Civil Appeal No. 12 of 2018 - Rajesh, Suresh vs State", "Civil Appeal No. 12 of 2018 - [R] vs [R]"},
+		{"Rajesh, Civil Appeal No. 12 of 2018 - Suresh vs State", "[R] vs [R]"},
+		{"Rajesh vs State (Criminal Appeal No. 5 of 2019)", "[R] vs [R] (Criminal Appeal No. 5 of 2019)"},
+	}
+	for _, tt := range tests {
+		if got := RedactCauseTitle(tt.title, "[R]"); got != tt.want {
+			t.Errorf("RedactCauseTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRedirectedPageLinks(t *testing.T) {
+	var loops atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/judgments/" && r.URL.Query().Get("judgment_year") == "2018":
+			http.Redirect(w, r, "/archive/2018/list.html", http.StatusFound)
+		case r.URL.Path == "/archive/2018/list.html":
+			io.WriteString(w, yearPage(row(1, "12-03-2018", "A vs B", "Tax", "s", "docs/a.pdf")))
+		default:
+			loops.Add(1)
+			http.Redirect(w, r, r.URL.String(), http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+	// the default client, which bounds redirects
+	sc := &Scraper{BaseURL: srv.URL + "/judgments/", Logger: discard}
+
+	js, stats, err := sc.fetchYear(context.Background(), 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := srv.URL + "/archive/2018/list.html"; stats.FinalURL != want {
+		t.Errorf("FinalURL = %q, want %q", stats.FinalURL, want)
+	}
+	if want := srv.URL + "/archive/2018/docs/a.pdf"; len(js) != 1 || js[0].PDFLink != want {
+		t.Errorf("judgments = %+v, want the link resolved against the final URL %q", js, want)
+	}
+
+	_, _, err = sc.fetchYear(context.Background(), 2019)
+	if err == nil || !strings.Contains(err.Error(), "stopped after 5 redirects") {
+		t.Errorf("redirect loop: err = %v", err)
+	}
+	if n := loops.Load(); n != maxRedirects {
+		t.Errorf("followed the loop %d times, want %d", n, maxRedirects)
+	}
+}
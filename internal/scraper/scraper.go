@@ -1,66 +1,825 @@
 package scraper
 
 import (
-	"encoding/json"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
 )
 
 // Judgment represents a single row from the landmark judgments table.
 type Judgment struct {
+	Year             int    `json:"year,omitempty"`
 	DateOfJudgment   string `json:"judgment_date"`
 	CauseTitleCaseNo string `json:"cause_title_case_no"`
-	Subject          string `json:"subject"`
-	JudgmentSummary  string `json:"judgment_summary"`
-	PDFLink          string `json:"pdf_link"`
+	// CaseType, CaseNumber and CaseYear are parsed from CauseTitleCaseNo
+	// when it carries a recognized case number, e.g. "Civil Appeal", "1234"
+	// and "2018".
+	CaseType        string `json:"case_type,omitempty"`
+	CaseNumber      string `json:"case_number,omitempty"`
+	CaseYear        string `json:"case_year,omitempty"`
+	Subject         string `json:"subject"`
+	JudgmentSummary string `json:"judgment_summary"`
+	PDFLink         string `json:"pdf_link"`
+	DetailLink      string `json:"detail_link,omitempty"`
+	// Bench lists the judges named in a bench or coram column, without
+	// their honorifics.
+	Bench []string `json:"bench,omitempty"`
+	// PDFPath is set by Scraper.DownloadPDFs: where the PDF was saved,
+	// relative to the output directory and slash-separated.
+	PDFPath string `json:"pdf_path,omitempty"`
+	// PDFOK is set by Scraper.VerifyPDFs: whether PDFLink served a PDF.
+	PDFOK     *bool  `json:"pdf_ok,omitempty"`
+	SummaryEN string `json:"summary_en,omitempty"`
+	SummaryHI string `json:"summary_hi,omitempty"`
+
+	SourceLastModified string `json:"source_last_modified,omitempty"`
+
+	// ContentHash, when set, is Hash() recorded for change tracking.
+	ContentHash string `json:"hash,omitempty"`
+
+	// ParsedDate is DateOfJudgment parsed, zero when the format is not
+	// recognized. It is encoded as parsed_date (YYYY-MM-DD) and omitted when
+	// zero; DateOfJudgment keeps the raw text.
+	ParsedDate time.Time `json:"-"`
 }
 
-func isNumericShort(s string) bool {
-	s = strings.TrimSpace(s)
-	if s == "" || len(s) > 6 {
-		return false
+// isHindiHeader reports whether a lower-cased header names a Hindi column.
+func isHindiHeader(lower string) bool {
+	return strings.Contains(lower, "hindi") || strings.Contains(lower, "हिंदी") || strings.Contains(lower, "हिन्दी")
+}
+
+// DefaultBaseURL is the Supreme Court of India landmark judgment summaries page.
+const DefaultBaseURL = "https://www.sci.gov.in/landmark-judgment-summaries/"
+
+// DefaultUserAgent identifies the scraper when Scraper.UserAgent is empty.
+const DefaultUserAgent = "sci-scraper/1.0"
+
+// Scraper holds optional configuration for a scrape. The zero value is ready
+// to use and behaves like the package-level functions.
+type Scraper struct {
+	// BaseURL is the landmark judgment summaries page; the judgment_year
+	// query parameter is added to it. Empty means DefaultBaseURL.
+	BaseURL string
+
+	// HTTPClient is used for all requests. Nil means a client with a 30s
+	// timeout. Inject a client whose http.Transport sets Proxy to route
+	// requests through a proxy, or to change TLS settings.
+	HTTPClient *http.Client
+
+	// UserAgent is sent as the User-Agent header on every request. Empty
+	// means DefaultUserAgent.
+	UserAgent string
+
+	// RequestHeaders are set on every request, after the scraper's own
+	// headers; a "User-Agent" entry overrides UserAgent.
+	RequestHeaders map[string]string
+
+	// Cookies are sent with every request to the base URL's site, through
+	// a cookie jar on a copy of the HTTP client. The jar is made on the
+	// first request and kept, so cookies the site sets in reply, such as a
+	// session, are sent on later pages and PDF requests too. Cookies are
+	// ignored when HTTPClient has a jar of its own.
+	Cookies []*http.Cookie
+	// jar is the cookie jar holding Cookies, guarded by jarMu
+	jar http.CookieJar
+
+	// Limiter, when set, is waited on before every request. Share one
+	// limiter between scrapers to bound their combined request rate.
+	Limiter *rate.Limiter
+
+	// Logger receives progress messages. Nil means the standard library's
+	// default logger.
+	Logger Logger
+	// Retries is how many times ScrapeYearsConcurrent retries a year after a
+	// retryable failure, waiting BackoffDelay(attempt, RetryDelay,
+	// MaxRetryDelay) in between. Zero delays retry immediately.
+	Retries                   int
+	RetryDelay, MaxRetryDelay time.Duration
+	// YearTimeout, when positive, bounds each attempt ScrapeYearsConcurrent
+	// makes at a year; an attempt that runs out fails wrapping
+	// context.DeadlineExceeded and is retried like any retryable failure.
+	YearTimeout time.Duration
+	// YearDelay is how long each ScrapeYearsConcurrent worker pauses
+	// between one year and the next.
+	YearDelay time.Duration
+	// OnYearDone, when set, is called by ScrapeYearsConcurrent with the
+	// outcome of each year once it is settled, including years that
+	// cancellation kept from starting. Workers call it concurrently.
+	OnYearDone func(YearResult)
+
+	// Metrics, when set, is told about requests, parsed and written
+	// judgments, retries and finished years.
+	Metrics Metrics
+
+	// LogLevel selects which messages reach Logger; see LogLevel.
+	LogLevel LogLevel
+
+	// Progress, when set, receives a ProgressEvent as each year moves
+	// through fetching, parsing, writing and done or error. Sends never
+	// block; events are dropped while the channel is full. The caller owns
+	// the channel and closes it once scraping has finished.
+	Progress chan<- ProgressEvent
+
+	// PersistFunc, when set, fully replaces the default file output: the
+	// scraper fetches and parses a year and hands the judgments off to it.
+	// No directory is created and no JSON file is written in outDir.
+	PersistFunc func(year int, judgments []Judgment) error
+
+	// Transform, when set, post-processes each fetched year — filtering,
+	// deduplicating, redacting or sorting it — before its PDFs are checked
+	// or downloaded and it is persisted, so that those steps only see the
+	// judgments that are written.
+	Transform func(year int, judgments []Judgment) []Judgment
+
+	// MultiLang captures English and Hindi summaries into SummaryEN and
+	// SummaryHI when the table has language-specific columns (detected by
+	// header text) or the summary cell marks its parts with a lang attribute.
+	MultiLang bool
+
+	// Stamp records the source page's Last-Modified response header on each
+	// judgment as SourceLastModified. It stays empty when the header is absent.
+	Stamp bool
+
+	// RawText keeps Subject and JudgmentSummary exactly as the cell text
+	// reads instead of collapsing runs of whitespace into single spaces.
+	RawText bool
+
+	// Preprocess, when set, rewrites the raw page HTML before parsing, e.g. to
+	// work around markup the parser mishandles. Nil passes the page through.
+	Preprocess PreprocessFunc
+
+	// Format selects the file format written by ScrapeYear. Empty means
+	// FormatJSON.
+	Format Format
+
+	// CacheDir, when set, keeps each raw page fetched, keyed by its URL, as
+	// <year>-<hash>.html (with its metadata in <year>-<hash>.json) and
+	// reuses it instead of fetching while it is younger than CacheTTL. Older
+	// entries are revalidated with If-None-Match/If-Modified-Since and reused
+	// when the server answers 304. A zero CacheTTL never expires entries.
+	CacheDir string
+	CacheTTL time.Duration
+	// RefreshCache fetches every page afresh, replacing cached entries.
+	RefreshCache bool
+
+	// MinYear and MaxYear bound the years that may be scraped. Zero means
+	// DefaultMinYear and the current calendar year respectively.
+	MinYear, MaxYear int
+
+	// FileTemplate, when set, is a text/template rendering each year's
+	// file path relative to the output directory from {{.Year}} and
+	// {{.Format}}, e.g. "{{.Year}}/sci.{{.Format}}". Directories on the
+	// path are created as needed.
+	FileTemplate string
+
+	// DBPath is the SQLite database FormatSQLite upserts into; empty means
+	// SQLiteFileName in the output directory.
+	DBPath string
+
+	// CSVBOM starts FormatCSV output with a UTF-8 byte order mark, which
+	// Excel needs to read the file as UTF-8.
+	CSVBOM bool
+
+	// Gzip compresses the per-year files written by WriteYear, appending .gz
+	// to their names. It does not apply to FormatSQLite.
+	Gzip bool
+
+	// SubjectFilter, when set, keeps only judgments whose Subject contains it,
+	// ignoring case. A year without matches fails with an error wrapping
+	// ErrNoMatches.
+	SubjectFilter string
+
+	// FromDate and ToDate, when non-zero, drop judgments dated outside
+	// FromDate..ToDate (inclusive). Judgments whose date cannot be parsed are
+	// kept unless DropUnparsedDates is set. A year left without judgments
+	// fails with an error wrapping ErrNoDateMatches.
+	FromDate, ToDate  time.Time
+	DropUnparsedDates bool
+
+	// Limit, when positive, stops parsing a year's page once this many
+	// judgments have passed the filters, Dedup and Seen, keeping the first
+	// Limit in table order.
+	Limit int
+
+	// Dedup drops each judgment that duplicates one already kept for the
+	// year, as by the Dedup function, while rows are parsed.
+	Dedup bool
+
+	// Seen, when set, drops the judgments it has recorded for the year while
+	// rows are parsed, so that only those not seen before are kept. A year
+	// left with none is not an error. The scraper does not record what it
+	// keeps; call Seen.Record once the year is written.
+	Seen *SeenState
+
+	// KeepPartial, when set, keeps what a year had gathered when its context
+	// is done instead of discarding it: the judgments of the result pages
+	// parsed so far, or a fetched year whose PDF checks or downloads were cut
+	// short, are still persisted. The year's error then wraps ErrPartial and
+	// ctx.Err(). Output files are replaced whole, so a partial year replaces
+	// any complete one written earlier.
+	KeepPartial bool
+
+	// PDFFilter keeps only judgments with a PDF link (PDFRequired) or only
+	// those without one (PDFMissing). A year left without judgments fails
+	// with an error wrapping ErrNoMatches.
+	PDFFilter PDFFilter
+
+	// OnJudgment, when set, is called with each judgment in table order as
+	// it is parsed, after filtering. An error aborts the year and is
+	// returned wrapped.
+	OnJudgment func(Judgment) error
+
+	// DownloadPDFs makes ScrapeYear also download each judgment's PDF into
+	// outDir/pdfs/<year>/, skipping files already on disk, before writing
+	// the year, and record where in Judgment.PDFPath. PDFConcurrency
+	// downloads run at once; zero means DefaultPDFConcurrency.
+	DownloadPDFs   bool
+	PDFConcurrency int
+
+	// TrackingParams lists query parameters, compared ignoring case, that
+	// are stripped from PDF links so that a link stays the same between
+	// runs. Nil means DefaultTrackingParams; "utm_" parameters are always
+	// stripped.
+	TrackingParams []string
+
+	// Strict makes ScrapeYear check judgments with ValidateJudgments and
+	// fail the year, writing nothing, when they don't pass.
+	Strict bool
+
+	// SaveHTMLDir, when set, keeps the page of each year fetched as
+	// <year>.html in this directory, before it is parsed, with the URLs it
+	// was requested as and served from and the fetch time in
+	// <year>.meta.json. Later result pages are saved as <year>-p<n>.
+	SaveHTMLDir string
+
+	// Checksum makes WriteYear write a sha256sum-style sidecar, named with
+	// ChecksumSuffix, next to each per-year file; see VerifyChecksum. It
+	// does not apply to FormatSQLite.
+	Checksum bool
+
+	// VerifyPDFs makes ScrapeYear check that each PDF link serves a PDF
+	// before persisting, recording the result in Judgment.PDFOK.
+	// VerifyConcurrency checks run at once, all waiting on Limiter; zero
+	// means DefaultVerifyConcurrency.
+	VerifyPDFs        bool
+	VerifyConcurrency int
+
+	// ColumnMap routes table columns to fields by header text, ahead of the
+	// built-in header matching; see ColumnMap.
+	ColumnMap ColumnMap
+
+	// SerialColumn says where the table's serial-number column is, if any.
+	// The default, SerialAuto, detects a short numeric first or last cell.
+	SerialColumn SerialPosition
+
+	// Parser names the registered Parser reading year pages; empty selects
+	// the first one detecting the page, falling back to DefaultParser.
+	Parser string
+
+	// PageConcurrency, when above 1, fetches the result pages listed by a
+	// year's first page (its numbered pagination links) this many at a
+	// time, all waiting on Limiter. They are still parsed as the next links
+	// reach them, so judgments keep their page order; pages the first one
+	// does not list are fetched in turn.
+	PageConcurrency int
+	// PageRetries is how many times a retryable failure (see IsRetryable)
+	// fetching a paginated year's later result page is retried, waiting
+	// BackoffDelay(attempt, RetryDelay, MaxRetryDelay) in between. A page
+	// still failing after that ends the year with the judgments of the
+	// pages before it: they are returned, and persisted by ScrapeYear, with
+	// an error wrapping ErrPartial, and Stats.FailedPage is set.
+	PageRetries int
+}
+
+// Logger is the minimal logging interface used by Scraper. *log.Logger
+// satisfies it; adapters for other logging libraries need only Printf.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// SerialPosition is the position of a table's serial-number column.
+type SerialPosition int
+
+const (
+	// SerialAuto detects a leading or trailing serial column per row.
+	SerialAuto SerialPosition = iota
+	// SerialNone treats every column as data.
+	SerialNone
+	// SerialFirst skips the first column.
+	SerialFirst
+	// SerialLast skips the last column.
+	SerialLast
+)
+
+// PDFFilter selects judgments by whether they have a PDF link.
+type PDFFilter int
+
+const (
+	// PDFAny keeps every judgment.
+	PDFAny PDFFilter = iota
+	// PDFRequired drops judgments without a PDF link.
+	PDFRequired
+	// PDFMissing keeps only judgments without a PDF link.
+	PDFMissing
+)
+
+// keep reports whether f lets j through.
+func (f PDFFilter) keep(j Judgment) bool {
+	switch f {
+	case PDFRequired:
+		return j.PDFLink != ""
+	case PDFMissing:
+		return j.PDFLink == ""
 	}
-	for _, r := range s {
-		if r < '0' || r > '9' {
-			return false
+	return true
+}
+
+var defaultScraper = &Scraper{}
+
+// DefaultMinYear is the first year the site publishes summaries for and the
+// default for Scraper.MinYear.
+const DefaultMinYear = 2016
+
+// yearBounds applies the defaults for MinYear and MaxYear: DefaultMinYear
+// and the current calendar year.
+func yearBounds(minYear, maxYear int) (int, int) {
+	if minYear == 0 {
+		minYear = DefaultMinYear
+	}
+	if maxYear == 0 {
+		maxYear = time.Now().Year()
+	}
+	return minYear, maxYear
+}
+
+// YearRange returns the first and last year the scraper accepts, with the
+// defaults of MinYear and MaxYear applied.
+func (sc *Scraper) YearRange() (minYear, maxYear int) {
+	return yearBounds(sc.MinYear, sc.MaxYear)
+}
+
+// maxRedirects bounds the redirects defaultHTTPClient follows, so that a
+// redirect loop fails fast.
+const maxRedirects = 5
+
+// defaultHTTPClient is used when Scraper.HTTPClient is nil.
+var defaultHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
 		}
+		return nil
+	},
+}
+
+func (sc *Scraper) client() *http.Client {
+	if sc.HTTPClient != nil {
+		return sc.withCookies(sc.HTTPClient)
 	}
-	return true
+	return sc.withCookies(defaultHTTPClient)
+}
+
+// wait blocks until Limiter allows another request.
+func (sc *Scraper) wait(ctx context.Context) error {
+	if sc.Limiter == nil {
+		return nil
+	}
+	if err := sc.Limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// LogLevel controls how much a Scraper logs. Warnings are logged at every
+// level.
+type LogLevel int
+
+const (
+	// LogNormal, the default, logs per-year progress.
+	LogNormal LogLevel = 0
+	// LogQuiet logs only warnings.
+	LogQuiet LogLevel = -1
+	// LogVerbose also logs request URLs, cache use and parse statistics.
+	LogVerbose LogLevel = 1
+)
+
+// Logf logs a message to Logger if LogLevel is at least level.
+func (sc *Scraper) Logf(level LogLevel, format string, args ...any) {
+	if sc.LogLevel < level {
+		return
+	}
+	if sc.Logger != nil {
+		sc.Logger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+func (sc *Scraper) logf(format string, args ...any) { sc.Logf(LogNormal, format, args...) }
+
+func (sc *Scraper) debugf(format string, args ...any) { sc.Logf(LogVerbose, format, args...) }
+
+func (sc *Scraper) warnf(format string, args ...any) { sc.Logf(LogQuiet, format, args...) }
+
+func (sc *Scraper) userAgent() string {
+	if sc.UserAgent != "" {
+		return sc.UserAgent
+	}
+	return DefaultUserAgent
 }
 
 // ScrapeYear fetches the page for a given year and writes a JSON file in outDir.
 func ScrapeYear(year int, outDir string) error {
-	if year < 2016 || year > 2025 {
-		return errors.New("year out of supported range 2016..2025")
+	return defaultScraper.ScrapeYear(year, outDir)
+}
+
+// ScrapeYearWithContext is like ScrapeYear but honors ctx for cancellation
+// and timeouts.
+func ScrapeYearWithContext(ctx context.Context, year int, outDir string) error {
+	return defaultScraper.ScrapeYearWithContext(ctx, year, outDir)
+}
+
+// ScrapeYear fetches the page for a given year and persists the judgments,
+// either through PersistFunc or as a JSON file in outDir.
+func (sc *Scraper) ScrapeYear(year int, outDir string) error {
+	return sc.ScrapeYearWithContext(context.Background(), year, outDir)
+}
+
+// ScrapeYearWithContext is like ScrapeYear but honors ctx through the fetch
+// and parse. If ctx is done, it returns ctx.Err() wrapped with the year.
+func (sc *Scraper) ScrapeYearWithContext(ctx context.Context, year int, outDir string) error {
+	start := time.Now()
+	judgments, err := sc.FetchYearWithContext(ctx, year)
+	if err == nil || errors.Is(err, ErrPartial) {
+		// a partial fetch keeps its error unless persisting fails outright
+		if _, perr := sc.persist(ctx, year, outDir, judgments); perr != nil && (err == nil || !errors.Is(perr, ErrPartial)) {
+			err = perr
+		}
+		if errors.Is(err, ErrPartial) {
+			sc.warnf("year %d: cut short; wrote the %d judgments read before it stopped", year, len(judgments))
+		}
 	}
-	pageURL := fmt.Sprintf("https://www.sci.gov.in/landmark-judgment-summaries/?judgment_year=%d", year)
-	resp, err := http.Get(pageURL)
+	sc.metrics().ScrapeDone(year, time.Since(start), err)
 	if err != nil {
+		sc.progress(ProgressEvent{Year: year, Phase: PhaseError, Err: err})
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("fetch failed: %s - %s", resp.Status, string(body))
+	sc.progress(ProgressEvent{Year: year, Phase: PhaseDone, Judgments: len(judgments)})
+	return nil
+}
+
+// Persist runs the steps ScrapeYear takes once a year is fetched: Strict
+// validation, Transform, PDF checks and downloads if asked for, and then
+// PersistFunc or WriteYear into outDir. It lets judgments parsed some other
+// way, such as with ParseHTML, be stored like fetched ones.
+func (sc *Scraper) Persist(ctx context.Context, year int, outDir string, judgments []Judgment) error {
+	_, err := sc.persist(ctx, year, outDir, judgments)
+	return err
+}
+
+// persist implements Persist, also returning the judgments as transformed
+// and with their PDF paths, as they were persisted.
+func (sc *Scraper) persist(ctx context.Context, year int, outDir string, judgments []Judgment) ([]Judgment, error) {
+	// a year with nothing new to Seen is not broken
+	if sc.Strict && (len(judgments) > 0 || sc.Seen == nil) {
+		if err := ValidateJudgments(judgments); err != nil {
+			return nil, fmt.Errorf("year %d: %w", year, err)
+		}
 	}
+	if sc.Transform != nil {
+		judgments = sc.Transform(year, judgments)
+	}
+	if sc.VerifyPDFs {
+		sc.verifyPDFs(ctx, year, judgments)
+		if ctx.Err() != nil && !sc.KeepPartial {
+			return nil, fmt.Errorf("year %d: %w", year, ctx.Err())
+		}
+	}
+	// PDFs are fetched first so that their paths are recorded; the year is
+	// still written when some fail, and their errors returned afterwards
+	var downloadErr error
+	if sc.DownloadPDFs {
+		downloadErr = sc.downloadYearPDFs(ctx, year, outDir, judgments)
+		if ctx.Err() != nil && !sc.KeepPartial {
+			return nil, downloadErr
+		}
+	}
+	sc.progress(ProgressEvent{Year: year, Phase: PhaseWriting, Judgments: len(judgments)})
+	var err error
+	if sc.PersistFunc != nil {
+		err = sc.PersistFunc(year, judgments)
+	} else {
+		err = sc.WriteYear(outDir, year, judgments)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sc.metrics().Written(year, len(judgments))
+	if (sc.VerifyPDFs || sc.DownloadPDFs) && ctx.Err() != nil {
+		return judgments, fmt.Errorf("year %d: %w: %w", year, ErrPartial, ctx.Err())
+	}
+	return judgments, downloadErr
+}
+
+// FetchYear fetches and parses the page for a given year and returns its
+// judgments without touching the filesystem.
+func FetchYear(year int) ([]Judgment, error) {
+	return defaultScraper.FetchYear(year)
+}
+
+// FetchYearWithContext is like FetchYear but honors ctx.
+func FetchYearWithContext(ctx context.Context, year int) ([]Judgment, error) {
+	return defaultScraper.FetchYearWithContext(ctx, year)
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// FetchYear fetches and parses the page for a given year and returns its
+// judgments. When the page has no judgments it returns a nil slice and an
+// error wrapping ErrEmptyYear if the page says it has none, or else
+// ErrNoJudgments.
+func (sc *Scraper) FetchYear(year int) ([]Judgment, error) {
+	return sc.FetchYearWithContext(context.Background(), year)
+}
+
+// FetchYearWithContext is like FetchYear but honors ctx through the fetch and
+// parse. If ctx is done, it returns ctx.Err() wrapped with the year; with
+// KeepPartial, the judgments read by then are returned too, with an error
+// wrapping ErrPartial.
+func (sc *Scraper) FetchYearWithContext(ctx context.Context, year int) ([]Judgment, error) {
+	judgments, _, err := sc.fetchYear(ctx, year)
+	return judgments, err
+}
+
+// FetchYearWithStats is like FetchYear but also returns parse diagnostics,
+// which are filled in as far as the scrape got even when it fails.
+func FetchYearWithStats(year int) ([]Judgment, Stats, error) {
+	return defaultScraper.FetchYearWithStats(year)
+}
+
+// FetchYearWithStats is like FetchYear but also returns parse diagnostics,
+// which are filled in as far as the scrape got even when it fails.
+func (sc *Scraper) FetchYearWithStats(year int) ([]Judgment, Stats, error) {
+	return sc.fetchYear(context.Background(), year)
+}
+
+// fetchYear fetches and parses a year under ctx, collecting Stats as it goes.
+// Result pages linked as the next one are followed, up to maxPages, until
+// one has no next link, points back at a page already read, or Limit or an
+// OnJudgment error ends the parse.
+func (sc *Scraper) fetchYear(ctx context.Context, year int) ([]Judgment, Stats, error) {
+	return sc.fetchInto(ctx, &yearParse{year: year})
+}
+
+// fetchInto is fetchYear accumulating into yp, whose year is the one
+// fetched.
+func (sc *Scraper) fetchInto(ctx context.Context, yp *yearParse) ([]Judgment, Stats, error) {
+	year := yp.year
+	var stats Stats
+	if minYear, maxYear := yearBounds(sc.MinYear, sc.MaxYear); year < minYear || year > maxYear {
+		return nil, stats, fmt.Errorf("%w %d..%d", ErrYearOutOfRange, minYear, maxYear)
+	}
+	pageURL, err := sc.yearURL(year)
 	if err != nil {
-		return err
+		return nil, stats, err
+	}
+	sc.progress(ProgressEvent{Year: year, Phase: PhaseFetching})
+	pg, err := sc.loadPage(ctx, year, pageURL)
+	if err != nil {
+		return nil, stats, err
+	}
+	yp.stats.FinalURL = pg.url.String()
+	// pages already requested, against next links that loop back
+	seen := map[string]bool{pageURL: true, pg.url.String(): true}
+	// with PageConcurrency, the pages the first one lists
+	var ahead *prefetch
+	defer func() { ahead.stop() }()
+	for n := 1; ; n++ {
+		if sc.SaveHTMLDir != "" {
+			if err := sc.saveSnapshot(year, n, pageURL, pg); err != nil {
+				return nil, yp.stats, fmt.Errorf("year %d: saving HTML snapshot: %w", year, err)
+			}
+		}
+		sc.progress(ProgressEvent{Year: year, Phase: PhaseParsing})
+		doc, err := sc.newDocument(ctx, year, pg.body)
+		if err != nil {
+			if sc.KeepPartial && ctx.Err() != nil {
+				break
+			}
+			return nil, yp.stats, err
+		}
+		if err := sc.parseInto(yp, doc, pg.url, pg.lastModified); err != nil {
+			return nil, yp.stats, err
+		}
+		if yp.done() || ctx.Err() != nil {
+			break
+		}
+		if n == 1 && sc.PageConcurrency > 1 {
+			ahead = sc.prefetchPages(ctx, year, listedPageURLs(doc, pg.url), seen, sc.PageConcurrency)
+		}
+		next := nextPageURL(doc, pg.url)
+		if next == "" || seen[next] {
+			break
+		}
+		if n >= maxPages {
+			sc.warnf("warning: year %d: stopped after %d pages", year, maxPages)
+			break
+		}
+		seen[next] = true
+		pageURL = next
+		sc.debugf("year %d: following page %d: %s", year, n+1, next)
+		if p := ahead.take(next); p != nil {
+			pg, err = p.pg, p.err
+		} else {
+			pg, err = sc.loadPage(ctx, year, next)
+		}
+		for attempt := 1; err != nil && attempt <= sc.PageRetries && IsRetryable(err) && ctx.Err() == nil; attempt++ {
+			sc.logf("year %d: page %d failed, retrying: %v", year, n+1, err)
+			if !pause(ctx, BackoffDelay(attempt, sc.RetryDelay, sc.MaxRetryDelay)) {
+				break
+			}
+			pg, err = sc.loadPage(ctx, year, next)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				if sc.KeepPartial {
+					break
+				}
+				return nil, yp.stats, err
+			}
+			yp.pageErr, yp.failedPage = err, n+1
+			break
+		}
+		seen[pg.url.String()] = true
+	}
+	return sc.finishYear(ctx, yp)
+}
+
+// ParseHTML parses a saved year page from r exactly as a fetched one,
+// including Preprocess, filters and OnJudgment. Relative links resolve
+// against base, normally the URL the page was fetched from.
+func (sc *Scraper) ParseHTML(r io.Reader, base string, year int) ([]Judgment, Stats, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	return sc.parsePage(context.Background(), raw, u, year, "")
+}
+
+// parsePage preprocesses a page body and parses it with parseDocument.
+func (sc *Scraper) parsePage(ctx context.Context, raw []byte, base *url.URL, year int, lastModified string) ([]Judgment, Stats, error) {
+	doc, err := sc.newDocument(ctx, year, raw)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	return sc.parseDocument(ctx, doc, base, year, lastModified)
+}
+
+// newDocument applies Preprocess to a page's body and parses it.
+func (sc *Scraper) newDocument(ctx context.Context, year int, raw []byte) (*goquery.Document, error) {
+	if sc.Preprocess != nil {
+		raw = sc.Preprocess(raw)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(raw))
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("year %d: %w", year, ctx.Err())
 	}
+	return doc, err
+}
+
+// parseDocument extracts a year's judgments from its parsed page with the
+// selected Parser, resolving relative links against base, and applies the
+// filters and OnJudgment.
+func (sc *Scraper) parseDocument(ctx context.Context, doc *goquery.Document, base *url.URL, year int, lastModified string) ([]Judgment, Stats, error) {
+	yp := &yearParse{year: year, stats: Stats{FinalURL: base.String()}}
+	if err := sc.parseInto(yp, doc, base, lastModified); err != nil {
+		return nil, yp.stats, err
+	}
+	return sc.finishYear(ctx, yp)
+}
+
+// yearParse accumulates the judgments and Stats of a year's pages.
+type yearParse struct {
+	year      int
+	stats     Stats
+	judgments []Judgment
+	// discard leaves judgments empty, for callers that take each row from
+	// OnJudgment; kept counts the rows passing every filter either way
+	discard bool
+	kept    int
+	// parsed counts non-empty rows, subjectMatched those passing
+	// SubjectFilter, dateMatched those also passing the date filter and
+	// pdfMatched those also passing PDFFilter; duplicates and seenBefore
+	// count the rows Dedup and Seen dropped after that. judgments holds the
+	// rows passing every filter
+	parsed, subjectMatched, dateMatched, pdfMatched int
+	duplicates, seenBefore                          int
+	// keys holds the Dedup keys of the rows kept, with Dedup
+	keys    map[string]bool
+	hookErr error
+	// pageErr is the error of the later result page that ended the year,
+	// page failedPage, after its retries
+	pageErr    error
+	failedPage int
+	// limited reports whether Limit cut the parse short
+	limited bool
+	// text is the first page's text, searched for the advertised total and
+	// an empty-state message
+	text string
+}
 
-	var judgments []Judgment
+// done reports whether OnJudgment failed or Limit was reached, so that no
+// further rows or pages are read.
+func (yp *yearParse) done() bool {
+	return yp.hookErr != nil || yp.limited
+}
+
+// keep counts a parsed judgment and adds it to yp's judgments if it passes
+// the filters and OnJudgment, reporting false once OnJudgment fails or Limit
+// is reached. Filters run per row so that OnJudgment only sees kept rows.
+func (sc *Scraper) keep(yp *yearParse, j Judgment) bool {
+	dateFiltered := !sc.FromDate.IsZero() || !sc.ToDate.IsZero()
+	yp.parsed++
+	if sc.SubjectFilter != "" && !strings.Contains(strings.ToLower(j.Subject), strings.ToLower(sc.SubjectFilter)) {
+		return true
+	}
+	yp.subjectMatched++
+	if dateFiltered && !inDateRange(j, sc.FromDate, sc.ToDate, !sc.DropUnparsedDates) {
+		return true
+	}
+	yp.dateMatched++
+	if !sc.PDFFilter.keep(j) {
+		return true
+	}
+	yp.pdfMatched++
+	if sc.Dedup {
+		key := dedupKey(j)
+		if yp.keys[key] {
+			yp.duplicates++
+			return true
+		}
+		if yp.keys == nil {
+			yp.keys = map[string]bool{}
+		}
+		yp.keys[key] = true
+	}
+	if sc.Seen != nil && sc.Seen.has(yp.year, j) {
+		yp.seenBefore++
+		return true
+	}
+	if sc.OnJudgment != nil {
+		if err := sc.OnJudgment(j); err != nil {
+			yp.hookErr = err
+			return false
+		}
+	}
+	yp.kept++
+	if !yp.discard {
+		yp.judgments = append(yp.judgments, j)
+	}
+	if sc.Limit > 0 && yp.kept >= sc.Limit {
+		yp.limited = true
+		return false
+	}
+	return true
+}
+
+// parseInto parses one of a year's pages into yp with the selected Parser,
+// resolving relative links against base and, with Stamp, recording
+// lastModified on its judgments.
+func (sc *Scraper) parseInto(yp *yearParse, doc *goquery.Document, base *url.URL, lastModified string) error {
+	year, stats := yp.year, &yp.stats
+	stats.Pages++
+	if stats.Pages == 1 {
+		yp.text = doc.Text()
+	}
+	columns, err := sc.ColumnMap.compile()
+	if err != nil {
+		return err
+	}
+	if !sc.Stamp {
+		lastModified = ""
+	}
 
 	// helper to resolve relative URLs
-	base := resp.Request.URL
 	resolve := func(href string) string {
 		href = strings.TrimSpace(href)
 		if href == "" {
@@ -76,22 +835,65 @@ func ScrapeYear(year int, outDir string) error {
 		return base.ResolveReference(u).String()
 	}
 
+	parser, parserName, err := sc.selectParser(doc)
+	if err != nil {
+		return err
+	}
+	if parser != nil {
+		sc.debugf("year %d: parsing with the %s parser", year, parserName)
+		js, err := parser.Parse(doc, base)
+		if err != nil {
+			return fmt.Errorf("year %d: %s parser: %w", year, parserName, err)
+		}
+		for _, j := range js {
+			j.Year = year
+			if j.ParsedDate.IsZero() {
+				j.ParsedDate, _ = parseJudgmentDate(j.DateOfJudgment)
+			}
+			if j.SourceLastModified == "" {
+				j.SourceLastModified = lastModified
+			}
+			if j.PDFLink == "" {
+				stats.MissingPDF++
+			}
+			if !sc.keep(yp, j) {
+				break
+			}
+		}
+	}
+
 	// Try to find the landmark table first, then fall back to the first table
 	sel := doc.Find(".landmark_judgment_summary table").First()
 	if sel.Length() == 0 {
 		sel = doc.Find("table").First()
 	}
 
-	if sel.Length() > 0 {
+	if parser == nil && sel.Length() > 0 {
 		// determine header mapping if present
 		headerMap := map[string]int{}
 		hasHeader := false
-		sel.Find("tr").First().Find("th").Each(func(i int, h *goquery.Selection) {
+		// header indexes are columns, counting a colspan cell once per column
+		headerCols := 0
+		sel.Find("tr").First().Find("th").Each(func(_ int, h *goquery.Selection) {
+			i := headerCols
+			headerCols += cellSpan(h, "colspan")
 			text := strings.TrimSpace(h.Text())
 			if text != "" {
 				hasHeader = true
 				lower := strings.ToLower(text)
+				if key, ok := columns.match(lower); ok {
+					headerMap[key] = i
+					return
+				}
 				switch {
+				case isSerialHeader(lower):
+					headerMap["serial"] = i
+				case sc.MultiLang && isHindiHeader(lower):
+					headerMap["summary_hi"] = i
+				case sc.MultiLang && strings.Contains(lower, "english"):
+					headerMap["summary_en"] = i
+				case isBenchHeader(lower):
+					headerMap["bench"] = i
 				case strings.Contains(lower, "date"):
 					headerMap["date"] = i
 				case strings.Contains(lower, "cause") || strings.Contains(lower, "case") || strings.Contains(lower, "title"):
@@ -106,14 +908,52 @@ func ScrapeYear(year int, outDir string) error {
 			}
 		})
 
-		sel.Find("tr").Each(func(i int, s *goquery.Selection) {
+		stats.HeaderDetected = stats.HeaderDetected || hasHeader
+		serialIdx, hasSerialHeader := headerMap["serial"]
+
+		var grid spanGrid
+		sel.Find("tr").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			stats.Rows++
 			// skip header row if present
 			if i == 0 && hasHeader {
-				return
+				return true
 			}
-			cols := s.Find("td")
+			cols, distinct := grid.expand(s.Find("td"))
 			if cols.Length() < 1 {
-				return
+				stats.SkippedEmpty++
+				return true
+			}
+			// a row made of far fewer cells than the header has columns, or
+			// of one cell spanning the table, is a notice or a broken row
+			// whose fields would land in the wrong places
+			if hasHeader && distinct*2 < headerCols || distinct == 1 && cols.Length() > 1 {
+				stats.IrregularRows++
+				sc.debugf("year %d: skipping row %d: %d cells for %d columns", year, i, distinct, max(headerCols, cols.Length()))
+				return true
+			}
+
+			// Detect a serial column and keep it out of the positional reads:
+			// shift skips a leading one, width drops a trailing one.
+			shift, width := 0, cols.Length()
+			switch sc.SerialColumn {
+			case SerialAuto:
+				switch {
+				case hasSerialHeader && serialIdx == 0:
+					shift = 1
+				case hasSerialHeader && serialIdx == headerCols-1 && width == headerCols:
+					width--
+				default:
+					shift = detectSerialShift(cols)
+					if shift == 0 && width >= minSerialColumns {
+						if last := cols.Eq(width - 1); isSerialCell(last.Text()) && last.Find("a").Length() == 0 {
+							width--
+						}
+					}
+				}
+			case SerialFirst:
+				shift = 1
+			case SerialLast:
+				width--
 			}
 
 			// helper to read by header mapping or fallback to positional logic
@@ -121,61 +961,277 @@ func ScrapeYear(year int, outDir string) error {
 				if idx, ok := headerMap[key]; ok && idx < cols.Length() {
 					return strings.TrimSpace(cols.Eq(idx).Text())
 				}
-				if pos < cols.Length() {
+				if pos < width {
 					return strings.TrimSpace(cols.Eq(pos).Text())
 				}
 				return ""
 			}
 
-			// Detect and skip a leading serial column if present (numeric short value)
-			shift := 0
-			if cols.Length() >= 5 {
-				first := strings.TrimSpace(cols.Eq(0).Text())
-				if isNumericShort(first) {
-					shift = 1
-				}
-			}
-
 			date := readBy("date", 0+shift)
 			cause := readBy("cause", 1+shift)
 			subject := readBy("subject", 2+shift)
 			summary := readBy("summary", 3+shift)
 
-			// find pdf link anywhere in the row: accept explicit .pdf links or site view-pdf handlers
-			pdf := ""
-			s.Find("a").EachWithBreak(func(i int, a *goquery.Selection) bool {
+			// language-specific summaries: dedicated columns first, then lang
+			// attributes inside the summary cell
+			summaryEN, summaryHI := "", ""
+			if sc.MultiLang {
+				if idx, ok := headerMap["summary_en"]; ok && idx < cols.Length() {
+					summaryEN = strings.TrimSpace(cols.Eq(idx).Text())
+				}
+				if idx, ok := headerMap["summary_hi"]; ok && idx < cols.Length() {
+					summaryHI = strings.TrimSpace(cols.Eq(idx).Text())
+				}
+				if summaryEN == "" && summaryHI == "" {
+					cell := cols.Eq(3 + shift)
+					if idx, ok := headerMap["summary"]; ok {
+						cell = cols.Eq(idx)
+					}
+					summaryEN = strings.TrimSpace(cell.Find(`[lang|="en"]`).First().Text())
+					summaryHI = strings.TrimSpace(cell.Find(`[lang|="hi"]`).First().Text())
+				}
+				if _, ok := headerMap["summary"]; !ok && (summaryEN != "" || summaryHI != "") {
+					if summaryEN != "" {
+						summary = summaryEN
+					} else {
+						summary = summaryHI
+					}
+				}
+			}
+
+			var bench []string
+			if idx, ok := headerMap["bench"]; ok && idx < cols.Length() {
+				bench = parseBench(cols.Eq(idx).Text())
+			}
+
+			if !sc.RawText {
+				subject = normalizeWhitespace(subject)
+				summary = normalizeWhitespace(summary)
+				summaryEN = normalizeWhitespace(summaryEN)
+				summaryHI = normalizeWhitespace(summaryHI)
+			}
+
+			// find pdf link anywhere in the row: accept explicit .pdf links or site view-pdf handlers;
+			// the first other navigable anchor (title or "read more" link) is the detail page
+			pdf, detail := "", ""
+			cols.Find("a").EachWithBreak(func(i int, a *goquery.Selection) bool {
 				if href, ok := a.Attr("href"); ok {
 					lh := strings.ToLower(strings.TrimSpace(href))
-					if strings.HasSuffix(lh, ".pdf") || strings.Contains(lh, "view-pdf") || strings.Contains(lh, "/view-pdf/") {
-						pdf = resolve(href)
-						return false
+					switch {
+					case strings.HasSuffix(lh, ".pdf") || strings.Contains(lh, "view-pdf") || strings.Contains(lh, "/view-pdf/"):
+						if pdf == "" {
+							pdf = sc.canonicalizePDFLink(resolve(href))
+						}
+					case detail == "" && isDetailHref(lh):
+						detail = resolve(href)
 					}
 				}
-				return true
+				return pdf == "" || detail == ""
 			})
 
-			if date != "" || cause != "" || subject != "" || summary != "" || pdf != "" {
-				judgments = append(judgments, Judgment{DateOfJudgment: date, CauseTitleCaseNo: cause, Subject: subject, JudgmentSummary: summary, PDFLink: pdf})
+			if date == "" && cause == "" && subject == "" && summary == "" && pdf == "" {
+				stats.SkippedEmpty++
+				return true
 			}
+			if pdf == "" {
+				stats.MissingPDF++
+			}
+			parsedDate, _ := parseJudgmentDate(date)
+			_, caseType, caseNumber, caseYear := parseCauseTitle(cause)
+			j := Judgment{Year: year, DateOfJudgment: date, CauseTitleCaseNo: cause, CaseType: caseType, CaseNumber: caseNumber, CaseYear: caseYear, Subject: subject, JudgmentSummary: summary, PDFLink: pdf, DetailLink: detail, Bench: bench, SummaryEN: summaryEN, SummaryHI: summaryHI, ParsedDate: parsedDate, SourceLastModified: lastModified}
+
+			return sc.keep(yp, j)
 		})
 	}
 
-	if len(judgments) == 0 {
-		return fmt.Errorf("no judgments found on page %s", pageURL)
+	return nil
+}
+
+// finishYear logs yp's totals and returns its judgments and Stats, or the
+// error for a year whose pages yielded no judgments or none that passed the
+// filters.
+func (sc *Scraper) finishYear(ctx context.Context, yp *yearParse) ([]Judgment, Stats, error) {
+	year, stats, pageURL := yp.year, &yp.stats, yp.stats.FinalURL
+	var interrupted error
+	if ctx.Err() != nil {
+		if !sc.KeepPartial || yp.kept == 0 {
+			return nil, *stats, fmt.Errorf("year %d: %w", year, ctx.Err())
+		}
+		interrupted = fmt.Errorf("year %d: %w after %d pages: %w", year, ErrPartial, stats.Pages, ctx.Err())
+	}
+	if yp.hookErr != nil {
+		return nil, *stats, fmt.Errorf("year %d: judgment callback: %w", year, yp.hookErr)
+	}
+	if yp.pageErr != nil {
+		if yp.kept == 0 {
+			return nil, *stats, fmt.Errorf("year %d: page %d: %w", year, yp.failedPage, yp.pageErr)
+		}
+		stats.FailedPage = yp.failedPage
+		sc.warnf("warning: year %d: page %d failed; keeping the %d judgments from the pages before it", year, yp.failedPage, yp.kept)
+		interrupted = fmt.Errorf("year %d: %w, page %d failed: %w", year, ErrPartial, yp.failedPage, yp.pageErr)
+	}
+	if yp.parsed == 0 {
+		if isEmptyPage(yp.text) {
+			return nil, *stats, fmt.Errorf("%w on page %s", ErrEmptyYear, pageURL)
+		}
+		return nil, *stats, fmt.Errorf("%w on page %s", ErrNoJudgments, pageURL)
+	}
+
+	sc.logf("year %d: parsed %d judgments", year, yp.parsed)
+	sc.debugf("year %d: %d table rows, %d empty, %d without a PDF link, header detected: %t", year, stats.Rows, stats.SkippedEmpty, stats.MissingPDF, stats.HeaderDetected)
+	stats.Parsed = yp.parsed
+	sc.metrics().Parsed(year, yp.parsed)
+	stats.Advertised = findAdvertised(yp.text)
+	if yp.limited {
+		sc.logf("year %d: stopped at the limit of %d judgments", year, sc.Limit)
+	} else if stats.CountMismatch() && interrupted == nil {
+		sc.warnf("warning: year %d: page advertises %d judgments but %d were parsed", year, stats.Advertised, yp.parsed)
+	}
+	if stats.suspicious() {
+		sc.warnf("warning: year %d: skipped %d of %d table rows as empty; the page layout may have changed", year, stats.SkippedEmpty, stats.dataRows())
 	}
 
-	if err := os.MkdirAll(outDir, 0o755); err != nil {
-		return err
+	if yp.subjectMatched == 0 {
+		return nil, *stats, fmt.Errorf("%w filter %q on page %s", ErrNoMatches, sc.SubjectFilter, pageURL)
 	}
-	outFile := filepath.Join(outDir, fmt.Sprintf("sci_judgments_%d.json", year))
-	f, err := os.Create(outFile)
+	if yp.dateMatched == 0 {
+		return nil, *stats, fmt.Errorf("%w on page %s", ErrNoDateMatches, pageURL)
+	}
+	if dropped := yp.dateMatched - yp.pdfMatched; dropped > 0 {
+		sc.logf("year %d: dropped %d judgments for the PDF link filter", year, dropped)
+	}
+	if yp.pdfMatched == 0 {
+		return nil, *stats, fmt.Errorf("%w PDF link filter on page %s", ErrNoMatches, pageURL)
+	}
+	if yp.duplicates > 0 {
+		sc.logf("year %d: dropped %d duplicate judgments", year, yp.duplicates)
+	}
+	if sc.Seen != nil {
+		sc.logf("year %d: %d of %d judgments not seen by earlier runs", year, yp.kept, yp.kept+yp.seenBefore)
+	}
+
+	return yp.judgments, *stats, interrupted
+}
+
+// isDetailHref reports whether a lower-cased href points at a page rather
+// than an in-page anchor, a script or a mail address.
+func isDetailHref(lh string) bool {
+	return lh != "" && !strings.HasPrefix(lh, "#") && !strings.HasPrefix(lh, "javascript:") && !strings.HasPrefix(lh, "mailto:")
+}
+
+// page is a year page's body, decompressed but not preprocessed, with the
+// URL it was finally served from (the base for relative links) and its
+// validators.
+type page struct {
+	body         []byte
+	url          *url.URL
+	lastModified string
+	etag         string
+	fetchedAt    time.Time
+}
+
+// loadPage returns the page at pageURL, from the cache when a fresh entry
+// exists and otherwise from the network, caching the result. A stale entry
+// is revalidated with a conditional request and reused on 304 Not Modified.
+func (sc *Scraper) loadPage(ctx context.Context, year int, pageURL string) (*page, error) {
+	var cached *page
+	if sc.CacheDir != "" && !sc.RefreshCache {
+		var fresh bool
+		if cached, fresh = sc.readCache(year, pageURL); fresh {
+			sc.debugf("year %d: using cached page", year)
+			return cached, nil
+		}
+	}
+	pg, err := sc.fetchPage(ctx, year, pageURL, cached)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if sc.CacheDir != "" {
+		if err := sc.writeCache(year, pageURL, pg); err != nil {
+			sc.warnf("warning: year %d: caching page: %v", year, err)
+		}
+	}
+	return pg, nil
+}
+
+// fetchPage requests pageURL and reads the whole response body. When cached
+// is set the request is made conditional on its validators, and cached is
+// returned if the server answers 304 Not Modified.
+func (sc *Scraper) fetchPage(ctx context.Context, year int, pageURL string, cached *page) (*page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	sc.setHeaders(req)
+	if err := sc.wait(ctx); err != nil {
+		return nil, fmt.Errorf("year %d: %w", year, err)
+	}
+	sc.debugf("fetching %s", pageURL)
+	resp, err := sc.do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("year %d: %w", year, ctx.Err())
+		}
+		return nil, &FetchError{URL: pageURL, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		sc.debugf("year %d: page not modified, using cached copy", year)
+		return cached, nil
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &FetchError{StatusCode: resp.StatusCode, URL: pageURL, Err: fmt.Errorf("%s - %s", resp.Status, string(body))}
+	}
+
+	// Accept-Encoding is set explicitly, so the transport leaves gzip
+	// bodies to us
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	// resp.Request is the last request made, so its URL is where the page
+	// was finally served from and the base its relative links need
+	final := resp.Request.URL
+	if final.String() != pageURL {
+		sc.debugf("year %d: redirected to %s", year, final)
+	}
+	raw, err := io.ReadAll(body)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("year %d: %w", year, ctx.Err())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &page{body: raw, url: final, lastModified: resp.Header.Get("Last-Modified"), etag: resp.Header.Get("ETag"), fetchedAt: time.Now()}, nil
+}
+
+// yearURL joins BaseURL with the judgment_year query parameter.
+func (sc *Scraper) yearURL(year int) (string, error) {
+	base := sc.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
 	}
-	defer f.Close()
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	// preserve characters like '&' in URLs instead of escaping to \u0026
-	enc.SetEscapeHTML(false)
-	return enc.Encode(judgments)
+	q := u.Query()
+	q.Set("judgment_year", strconv.Itoa(year))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
 }
@@ -0,0 +1,366 @@
+package scraper
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYearURL(t *testing.T) {
+	for _, tc := range []struct {
+		base, want string
+	}{
+		{"", DefaultBaseURL + "?judgment_year=2018"},
+		{"http://127.0.0.1:8080/judgments/", "http://127.0.0.1:8080/judgments/?judgment_year=2018"},
+		{"http://mirror.test/j?lang=en", "http://mirror.test/j?judgment_year=2018&lang=en"},
+		{"http://mirror.test/j?judgment_year=2001", "http://mirror.test/j?judgment_year=2018"},
+	} {
+		got, err := (&Scraper{BaseURL: tc.base}).yearURL(2018)
+		if err != nil || got != tc.want {
+			t.Errorf("yearURL with BaseURL %q = %q, %v; want %q", tc.base, got, err, tc.want)
+		}
+	}
+}
+
+func TestYearRange(t *testing.T) {
+	s := newSite(t, map[int]string{
+		1999: yearPage(row(1, "12-03-1999", "A vs B", "Tax", "s", "/a.pdf")),
+	}, nil)
+	sc := s.scraper()
+	sc.MinYear, sc.MaxYear = 1990, 2000
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	if err := sc.ScrapeYear(1999, t.TempDir()); err != nil {
+		t.Errorf("in range: %v", err)
+	}
+	for _, y := range []int{1989, 2001} {
+		if err := sc.ScrapeYear(y, t.TempDir()); !errors.Is(err, ErrYearOutOfRange) {
+			t.Errorf("year %d: err = %v, want ErrYearOutOfRange", y, err)
+		}
+	}
+	if n := s.hits("/judgments/"); n != 1 {
+		t.Errorf("made %d page requests, want none for years out of range", n)
+	}
+
+	now := time.Now().Year()
+	if lo, hi := (&Scraper{}).YearRange(); lo != DefaultMinYear || hi != now {
+		t.Errorf("default YearRange() = %d..%d, want %d..%d", lo, hi, DefaultMinYear, now)
+	}
+	def := s.scraper()
+	def.PersistFunc = sc.PersistFunc
+	if err := def.ScrapeYear(now+1, t.TempDir()); !errors.Is(err, ErrYearOutOfRange) {
+		t.Errorf("next year: err = %v, want ErrYearOutOfRange", err)
+	}
+	if err := def.ScrapeYear(DefaultMinYear-1, t.TempDir()); !errors.Is(err, ErrYearOutOfRange) {
+		t.Errorf("year before DefaultMinYear: err = %v, want ErrYearOutOfRange", err)
+	}
+}
+
+func TestScrapeYearFromFixture(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "first", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Land", "second", "view-pdf/?id=2"),
+	)}, nil)
+	out := t.TempDir()
+	if err := s.scraper().ScrapeYear(2018, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Judgment
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{s.URL + "/a.pdf", s.URL + "/judgments/view-pdf/?id=2"}
+	if len(got) != len(want) {
+		t.Fatalf("wrote %d judgments, want %d", len(got), len(want))
+	}
+	for i, j := range got {
+		if j.PDFLink != want[i] || j.Year != 2018 {
+			t.Errorf("judgment %d: link %q, year %d; want %q, 2018", i, j.PDFLink, j.Year, want[i])
+		}
+	}
+	if n := s.hits("/judgments/"); n != 1 {
+		t.Errorf("the year page was requested %d times, want 1", n)
+	}
+}
+
+func TestPersistFunc(t *testing.T) {
+	s := newSite(t, map[int]string{
+		2017: yearPage(row(1, "12-03-2017", "A vs B", "Tax", "first", "/a.pdf")),
+		2018: yearPage(
+			row(1, "12-03-2018", "C vs D", "Tax", "second", "/b.pdf"),
+			row(2, "13-03-2018", "E vs F", "Land", "third", "/c.pdf"),
+		),
+	}, nil)
+	sc := s.scraper()
+	sc.Transform = func(_ int, js []Judgment) []Judgment { return js[:1] }
+	persisted := map[int][]Judgment{}
+	sc.PersistFunc = func(y int, js []Judgment) error {
+		persisted[y] = js
+		if y == 2017 {
+			return errors.New("store unavailable")
+		}
+		return nil
+	}
+	// nothing is created under outDir
+	out := filepath.Join(t.TempDir(), "not", "created")
+	results, err := sc.ScrapeYears([]int{2017, 2018, 2019}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("outDir was created with PersistFunc set: %v", err)
+	}
+
+	if got := persisted[2018]; len(got) != 1 || got[0].Year != 2018 || got[0].JudgmentSummary != "second" || got[0].PDFLink != s.URL+"/b.pdf" {
+		t.Errorf("PersistFunc got %+v for 2018, want its first judgment after Transform", got)
+	}
+	if results[2018] != nil {
+		t.Errorf("2018: %v", results[2018])
+	}
+	if err := results[2017]; err == nil || !strings.Contains(err.Error(), "store unavailable") {
+		t.Errorf("2017: %v, want PersistFunc's error", err)
+	}
+	if _, called := persisted[2019]; called || results[2019] == nil {
+		t.Errorf("2019, which the site lacks: PersistFunc called %v, result %v", called, results[2019])
+	}
+}
+
+func TestFetchGzipEncodedPage(t *testing.T) {
+	page := yearPage(row(1, "12-03-2018", "A vs B", "Tax", "compressed", "/a.pdf"))
+	var accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		io.WriteString(gz, page)
+		gz.Close()
+	}))
+	defer srv.Close()
+	js, err := (&Scraper{BaseURL: srv.URL, Logger: discard}).FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "gzip" {
+		t.Errorf("sent Accept-Encoding %q, want gzip", accept)
+	}
+	if len(js) != 1 || js[0].JudgmentSummary != "compressed" {
+		t.Errorf("parsed %+v from the gzipped page", js)
+	}
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		io.WriteString(w, page)
+	}))
+	defer bad.Close()
+	if _, err := (&Scraper{BaseURL: bad.URL, Logger: discard}).FetchYear(2018); err == nil {
+		t.Error("a page claiming gzip that is not compressed was parsed")
+	}
+}
+
+func TestYearField(t *testing.T) {
+	page := yearPage(row(1, "12-03-2019", "A vs B", "Tax", "s", "/a.pdf"))
+	js, _, err := (&Scraper{}).ParseHTML(strings.NewReader(page), "https://example.org/", 2019)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 1 || js[0].Year != 2019 {
+		t.Fatalf("parsed %+v, want one judgment of 2019", js)
+	}
+	data, err := json.Marshal(js[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"year":2019`) {
+		t.Errorf("encoded without its year: %s", data)
+	}
+	if data, _ := json.Marshal(Judgment{}); strings.Contains(string(data), `"year"`) {
+		t.Errorf("a zero year was encoded: %s", data)
+	}
+
+	dir := t.TempDir()
+	if err := WriteMerged(dir, map[int][]Judgment{2016: {{CauseTitleCaseNo: "untagged"}}, 2019: js}); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, MergedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged []Judgment
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 2 || merged[0].Year != 2016 || merged[1].Year != 2019 {
+		t.Errorf("merged years %+v, want 2016 from the map key, then 2019", merged)
+	}
+}
+
+func TestStamp(t *testing.T) {
+	page := func(next string, rows ...string) string {
+		return `<html><head><link rel="next" href="` + next + `"></head><body><table>` + tableHeader + strings.Join(rows, "") + `</table></body></html>`
+	}
+	s := newSite(t, map[int]string{2018: page("/p2", row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf"))}, map[string]string{
+		"/p2": page("/p3", row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf")),
+		// served without a Last-Modified header
+		"/p3": page("", row(3, "14-03-2018", "E vs F", "Tax", "s3", "/c.pdf")),
+	})
+	s.lastModified = map[string]string{
+		"/judgments/": "Mon, 12 Mar 2018 10:00:00 GMT",
+		"/p2":         "Tue, 13 Mar 2018 10:00:00 GMT",
+	}
+	for _, stamp := range []bool{true, false} {
+		sc := s.scraper()
+		sc.Stamp = stamp
+		out := t.TempDir()
+		if err := sc.ScrapeYear(2018, out); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(out, "sci_judgments_2018.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Judgment
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"", "", ""}
+		if stamp {
+			// each judgment carries the header of the page it was on
+			want = []string{s.lastModified["/judgments/"], s.lastModified["/p2"], ""}
+		}
+		var stamps []string
+		for _, j := range got {
+			stamps = append(stamps, j.SourceLastModified)
+		}
+		if !slices.Equal(stamps, want) {
+			t.Errorf("Stamp %v: source_last_modified %q, want %q", stamp, stamps, want)
+		}
+		if n := strings.Count(string(data), `"source_last_modified"`); n != map[bool]int{true: 2}[stamp] {
+			t.Errorf("Stamp %v: the file has %d source_last_modified fields", stamp, n)
+		}
+	}
+}
+
+func TestCancelledScrapeKeepsEarlierFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	out := t.TempDir()
+	path := filepath.Join(out, YearFileName(2018, FormatJSON))
+	if err := os.WriteFile(path, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := (&Scraper{BaseURL: srv.URL, Logger: discard}).ScrapeYearWithContext(ctx, 2018, out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ScrapeYearWithContext = %v, want it cancelled", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+		t.Errorf("a cancelled scrape replaced the earlier file with %q", data)
+	}
+	if entries, _ := os.ReadDir(out); len(entries) != 1 {
+		t.Errorf("output directory holds %d entries, want only the earlier file", len(entries))
+	}
+}
+
+func TestOnJudgment(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "first", "Tax", "s", "/a.pdf"),
+		row(2, "13-03-2018", "second", "Land", "s", "/b.pdf"),
+		row(3, "14-03-2018", "third", "Tax", "s", "/c.pdf"),
+	)}, nil)
+	sc := s.scraper()
+	sc.SubjectFilter = "tax"
+	var seen []string
+	sc.OnJudgment = func(j Judgment) error {
+		seen = append(seen, j.CauseTitleCaseNo)
+		return nil
+	}
+	js, err := sc.FetchYear(2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "third"}; !slices.Equal(seen, want) || len(js) != 2 {
+		t.Errorf("OnJudgment saw %q and %d were returned, want %q", seen, len(js), want)
+	}
+
+	stop := errors.New("stop here")
+	seen = nil
+	sc.SubjectFilter = ""
+	sc.OnJudgment = func(j Judgment) error {
+		seen = append(seen, j.CauseTitleCaseNo)
+		if len(seen) == 2 {
+			return stop
+		}
+		return nil
+	}
+	var persisted bool
+	sc.PersistFunc = func(int, []Judgment) error {
+		persisted = true
+		return nil
+	}
+	if err := sc.ScrapeYear(2018, t.TempDir()); !errors.Is(err, stop) {
+		t.Errorf("ScrapeYear = %v, want the callback's error", err)
+	}
+	if len(seen) != 2 || persisted {
+		t.Errorf("after the callback failed: %d calls, persisted %t; want 2 calls and nothing persisted", len(seen), persisted)
+	}
+}
+
+func TestDetailLink(t *testing.T) {
+	cell := func(title, summary string) string {
+		return `<tr><td>1</td><td>12-03-2018</td><td>` + title + `</td><td>Tax</td><td>` + summary + `</td><td><a href="/a.pdf">PDF</a></td></tr>`
+	}
+	for _, tc := range []struct {
+		name, row, want string
+	}{
+		{"title link", cell(`<a href="detail/42">A vs B</a>`, "s"), "https://example.org/judgments/detail/42"},
+		{"read more", cell("A vs B", `s <a href="https://other.example/more?id=1">Read more</a>`), "https://other.example/more?id=1"},
+		{"anchors and scripts skipped", cell(`<a href="#top">A</a> <a href="javascript:void(0)">B</a>`, "s"), ""},
+		{"pdf only", cell("A vs B", "s"), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			page := yearPage(tc.row)
+			js, _, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(js) != 1 || js[0].DetailLink != tc.want || js[0].PDFLink != "https://example.org/a.pdf" {
+				t.Errorf("parsed %+v, want detail link %q", js, tc.want)
+			}
+		})
+	}
+}
+
+func TestWhitespaceCollapsed(t *testing.T) {
+	page := yearPage(row(1, "12-03-2018", "A vs B", "Civil\n\t  Appeal", "First line\n   second\tline\u00a0and&nbsp;more  ", "/a.pdf"))
+	for _, tc := range []struct {
+		raw              bool
+		subject, summary string
+	}{
+		{false, "Civil Appeal", "First line second line and more"},
+		{true, "Civil\n\t  Appeal", "First line\n   second\tline\u00a0and\u00a0more"},
+	} {
+		js, _, err := (&Scraper{RawText: tc.raw, Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/", 2018)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if js[0].Subject != tc.subject || js[0].JudgmentSummary != tc.summary {
+			t.Errorf("RawText %t: subject %q, summary %q; want %q, %q", tc.raw, js[0].Subject, js[0].JudgmentSummary, tc.subject, tc.summary)
+		}
+	}
+}
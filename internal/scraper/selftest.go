@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLayoutChanged is wrapped by SelfTest errors reporting that the page no
+// longer looks like the layout the parser expects.
+var ErrLayoutChanged = errors.New("site layout appears to have changed")
+
+// SelfTestOptions sets what SelfTest requires of the page. Zero fields take
+// the defaults noted.
+type SelfTestOptions struct {
+	// Year is the year fetched; default the last full year.
+	Year int
+	// MinJudgments is the fewest judgments the page must yield; default 5.
+	MinJudgments int
+	// MinComplete is the share of judgments, from 0 to 1, that must have a
+	// date, a cause title and a PDF link; default 0.8.
+	MinComplete float64
+}
+
+// SelfTest fetches one year and checks that the page still parses the way
+// it used to: a header row is detected, enough judgments are found and most
+// of them have their key fields filled in. Filters, including the PDF
+// link filter, Limit and OnJudgment are not applied. It returns the parse
+// statistics and, when a check fails, an error wrapping ErrLayoutChanged,
+// or the fetch error.
+func (sc *Scraper) SelfTest(ctx context.Context, opts SelfTestOptions) (Stats, error) {
+	minYear, maxYear := sc.YearRange()
+	if opts.Year == 0 {
+		opts.Year = max(maxYear-1, minYear)
+	}
+	if opts.MinJudgments <= 0 {
+		opts.MinJudgments = 5
+	}
+	if opts.MinComplete <= 0 {
+		opts.MinComplete = 0.8
+	}
+
+	check := *sc
+	check.SubjectFilter = ""
+	check.FromDate, check.ToDate = time.Time{}, time.Time{}
+	check.PDFFilter = PDFAny
+	check.Limit = 0
+	check.OnJudgment = nil
+	judgments, stats, err := check.fetchYear(ctx, opts.Year)
+	if errors.Is(err, ErrEmptyYear) {
+		return stats, fmt.Errorf("year %d: %w; pick a year with judgments", opts.Year, err)
+	}
+	if errors.Is(err, ErrNoJudgments) {
+		return stats, fmt.Errorf("year %d: %w: no judgment rows found", opts.Year, ErrLayoutChanged)
+	}
+	if err != nil {
+		return stats, err
+	}
+	if !stats.HeaderDetected {
+		return stats, fmt.Errorf("year %d: %w: no header row detected", opts.Year, ErrLayoutChanged)
+	}
+	if len(judgments) < opts.MinJudgments {
+		return stats, fmt.Errorf("year %d: %w: found %d judgments, want at least %d", opts.Year, ErrLayoutChanged, len(judgments), opts.MinJudgments)
+	}
+	complete := 0
+	for _, j := range judgments {
+		if j.DateOfJudgment != "" && j.CauseTitleCaseNo != "" && j.PDFLink != "" {
+			complete++
+		}
+	}
+	if share := float64(complete) / float64(len(judgments)); share < opts.MinComplete {
+		return stats, fmt.Errorf("year %d: %w: only %d of %d judgments have a date, cause title and PDF link", opts.Year, ErrLayoutChanged, complete, len(judgments))
+	}
+	return stats, nil
+}
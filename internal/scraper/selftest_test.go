@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// selfTestPage returns a year page of n complete rows.
+func selfTestPage(n int) string {
+	rows := make([]string, n)
+	for i := range rows {
+		rows[i] = row(i+1, "12-03-2018", fmt.Sprintf("P%d vs R%d", i, i), "Tax", "s", fmt.Sprintf("/%d.pdf", i))
+	}
+	return yearPage(rows...)
+}
+
+func TestSelfTestIgnoresFilters(t *testing.T) {
+	s := newSite(t, map[int]string{2018: selfTestPage(6)}, nil)
+	sc := s.scraper()
+	sc.SubjectFilter = "no such subject"
+	sc.PDFFilter = PDFMissing
+	sc.Limit = 2
+	if _, err := sc.SelfTest(context.Background(), SelfTestOptions{Year: 2018}); err != nil {
+		t.Errorf("SelfTest under filters: %v", err)
+	}
+}
+
+func TestSelfTestBrokenLayout(t *testing.T) {
+	headerless := `<html><body><table>` + strings.Repeat(row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"), 6) + `</table></body></html>`
+	incomplete := make([]string, 6)
+	for i := range incomplete {
+		incomplete[i] = row(i+1, "", fmt.Sprintf("P%d vs R%d", i, i), "Tax", "s", "")
+	}
+	for _, tc := range []struct {
+		name string
+		page string
+		opts SelfTestOptions
+		want string
+	}{
+		{"good", selfTestPage(6), SelfTestOptions{}, ""},
+		{"no table", `<html><body><div>Site under maintenance</div></body></html>`, SelfTestOptions{}, "no judgment rows found"},
+		{"no header", headerless, SelfTestOptions{}, "no header row detected"},
+		{"too few", selfTestPage(3), SelfTestOptions{}, "found 3 judgments, want at least 5"},
+		{"too few, lower threshold", selfTestPage(3), SelfTestOptions{MinJudgments: 2}, ""},
+		{"fields missing", yearPage(incomplete...), SelfTestOptions{}, "only 0 of 6 judgments"},
+		{"fields missing, above threshold", selfTestPage(6), SelfTestOptions{MinComplete: 1}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSite(t, map[int]string{2018: tc.page}, nil)
+			tc.opts.Year = 2018
+			_, err := s.scraper().SelfTest(context.Background(), tc.opts)
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("SelfTest failed: %v", err)
+			case tc.want != "" && (!errors.Is(err, ErrLayoutChanged) || !strings.Contains(err.Error(), tc.want)):
+				t.Errorf("SelfTest error = %v, want ErrLayoutChanged mentioning %q", err, tc.want)
+			}
+		})
+	}
+
+	s := newSite(t, nil, nil)
+	if _, err := s.scraper().SelfTest(context.Background(), SelfTestOptions{Year: 2018}); err == nil || errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("unreachable page: err = %v, want the fetch error", err)
+	}
+}
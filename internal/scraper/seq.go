@@ -0,0 +1,47 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// errStopSeq is returned from the OnJudgment hook of FetchYearSeq once the
+// consumer stops ranging, to end the parse.
+var errStopSeq = errors.New("sequence stopped")
+
+// FetchYearSeq is like FetchYearWithContext but yields the judgments one by
+// one as they are parsed, without collecting them.
+func FetchYearSeq(ctx context.Context, year int) iter.Seq2[Judgment, error] {
+	return defaultScraper.FetchYearSeq(ctx, year)
+}
+
+// FetchYearSeq fetches a year's page and yields its judgments, after the
+// filters and OnJudgment, as the rows are parsed. Breaking out of the range
+// stops parsing the remaining rows. The sequence ends with a zero Judgment
+// and the error on the first failure, including ctx being done between
+// yields; judgments already yielded stand.
+func (sc *Scraper) FetchYearSeq(ctx context.Context, year int) iter.Seq2[Judgment, error] {
+	return func(yield func(Judgment, error) bool) {
+		stopped := false
+		seq := *sc
+		seq.OnJudgment = func(j Judgment) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if sc.OnJudgment != nil {
+				if err := sc.OnJudgment(j); err != nil {
+					return err
+				}
+			}
+			if !yield(j, nil) {
+				stopped = true
+				return errStopSeq
+			}
+			return nil
+		}
+		if _, _, err := seq.fetchInto(ctx, &yearParse{year: year, discard: true}); err != nil && !stopped {
+			yield(Judgment{}, err)
+		}
+	}
+}
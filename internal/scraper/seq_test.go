@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFetchYearSeqBreakStopsFetching(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") +
+		row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf") + `</table></body></html>`
+	s := newSite(t, map[int]string{2018: page1}, map[string]string{
+		"/p2": yearPage(row(3, "14-03-2018", "E vs F", "Tax", "s3", "/c.pdf")),
+	})
+	sc := s.scraper()
+	var got []Judgment
+	for j, err := range sc.FetchYearSeq(context.Background(), 2018) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, j)
+		break
+	}
+	if len(got) != 1 || got[0].CauseTitleCaseNo != "A vs B" {
+		t.Fatalf("yielded %+v, want only the first row", got)
+	}
+	if n := s.hits("/p2"); n != 0 {
+		t.Errorf("the next page was fetched %d times after the range stopped", n)
+	}
+
+	got = got[:0]
+	for j, err := range sc.FetchYearSeq(context.Background(), 2018) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, j)
+	}
+	if len(got) != 3 {
+		t.Errorf("a full range yielded %d judgments, want 3", len(got))
+	}
+}
+
+func TestFetchYearSeqDoesNotCollect(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf"),
+	)}, nil)
+	sc := s.scraper()
+	sc.OnJudgment = func(Judgment) error { return nil }
+	yp := &yearParse{year: 2018, discard: true}
+	js, _, err := sc.fetchInto(context.Background(), yp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 0 || len(yp.judgments) != 0 || yp.kept != 2 {
+		t.Errorf("discarding parse returned %d, held %d, kept %d; want 0, 0, 2", len(js), len(yp.judgments), yp.kept)
+	}
+}
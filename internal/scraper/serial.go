@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minSerialColumns is the fewest cells a row needs before a numeric-looking
+// first or last cell is taken to be a serial number rather than data: four
+// data columns plus the serial, or three plus a serial and a PDF link column.
+const minSerialColumns = 4
+
+var (
+	// serialHeader matches header cells such as "S.No", "Sr. No.", "Sl No",
+	// "Serial" and "#".
+	serialHeader = regexp.MustCompile(`^(?:s\.?\s*no\.?|sr\.?(?:\s*no\.?)?|sl\.?\s*no\.?|serial(?:\s*no\.?)?|#)$`)
+	// serialPrefix matches labels written in front of a serial number.
+	serialPrefix = regexp.MustCompile(`(?i)^(?:s\.?\s*no|sr|sl|no)\.?\s*`)
+)
+
+func isNumericShort(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" || len(s) > 6 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isSerialHeader reports whether a lower-cased header names a serial column.
+func isSerialHeader(lower string) bool {
+	return serialHeader.MatchString(strings.TrimSpace(lower))
+}
+
+// isSerialCell reports whether a cell holds a serial number such as "7",
+// "007.", "7)" or "Sr. 7".
+func isSerialCell(s string) bool {
+	s = serialPrefix.ReplaceAllString(strings.TrimSpace(s), "")
+	s = strings.TrimRight(s, ".)")
+	return isNumericShort(s)
+}
+
+// detectSerialShift returns 1 when the row's first cell is a serial number
+// that should be skipped before reading data columns, and 0 otherwise.
+func detectSerialShift(cols *goquery.Selection) int {
+	if cols.Length() < minSerialColumns {
+		return 0
+	}
+	first := cols.Eq(0)
+	if first.Find("a").Length() > 0 || !isSerialCell(first.Text()) {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseTable parses a page holding one table of the given rows with sc.
+func parseTable(t *testing.T, sc *Scraper, rows ...string) []Judgment {
+	t.Helper()
+	page := `<html><body><table>` + strings.Join(rows, "") + `</table></body></html>`
+	js, _, err := sc.ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return js
+}
+
+func TestTrailingSerialColumn(t *testing.T) {
+	const data = `<td>12-03-2018</td><td>A vs B</td><td>Tax</td><td>the summary</td><td><a href="/a.pdf">PDF</a></td>`
+	for _, tc := range []struct {
+		name    string
+		serial  SerialPosition
+		rows    []string
+		subject string
+		summary string
+	}{
+		{"leading serial", SerialAuto, []string{`<tr><td>1</td>` + data + `</tr>`}, "Tax", "the summary"},
+		{"trailing serial", SerialAuto, []string{`<tr>` + data + `<td>1</td></tr>`}, "Tax", "the summary"},
+		{"trailing serial header", SerialAuto, []string{
+			`<tr><th>Date</th><th>Cause Title</th><th>Subject</th><th>Summary</th><th>View</th><th>S.No</th></tr>`,
+			`<tr>` + data + `<td>1</td></tr>`,
+		}, "Tax", "the summary"},
+		{"trailing serial with a label", SerialAuto, []string{`<tr>` + data + `<td>Sr. 12</td></tr>`}, "Tax", "the summary"},
+		{"forced last", SerialLast, []string{`<tr><td>12-03-2018</td><td>A vs B</td><td>Tax</td><td>the summary</td><td>7</td></tr>`}, "Tax", "the summary"},
+		{"numeric last cell of a short row", SerialAuto, []string{`<tr><td>12-03-2018</td><td>A vs B</td><td>7</td></tr>`}, "7", ""},
+		{"none keeps every column", SerialNone, []string{`<tr><td>1</td><td>12-03-2018</td><td>A vs B</td><td>Tax</td></tr>`}, "A vs B", "Tax"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			js := parseTable(t, &Scraper{SerialColumn: tc.serial}, tc.rows...)
+			if len(js) != 1 {
+				t.Fatalf("parsed %d judgments, want 1", len(js))
+			}
+			if js[0].Subject != tc.subject || js[0].JudgmentSummary != tc.summary {
+				t.Errorf("subject %q, summary %q; want %q, %q", js[0].Subject, js[0].JudgmentSummary, tc.subject, tc.summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package scraper
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// saveSnapshot writes page n of year to SaveHTMLDir as <year>.html (or
+// <year>-p<n>.html), as received apart from any Content-Encoding, and its
+// provenance to <year>.meta.json.
+func (sc *Scraper) saveSnapshot(year, n int, pageURL string, pg *page) error {
+	if err := os.MkdirAll(sc.SaveHTMLDir, 0o755); err != nil {
+		return err
+	}
+	base := filepath.Join(sc.SaveHTMLDir, pageFileName(year, n))
+	if err := writeFileAtomic(base+".html", func(w io.Writer) error {
+		_, err := w.Write(pg.body)
+		return err
+	}); err != nil {
+		return err
+	}
+	return writeMeta(base+".meta.json", cacheMeta{RequestURL: pageURL, URL: pg.url.String(), LastModified: pg.lastModified, ETag: pg.etag, FetchedAt: pg.fetchedAt})
+}
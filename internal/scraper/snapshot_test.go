@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveHTMLSnapshot(t *testing.T) {
+	page1 := `<html><head><link rel="next" href="/p2"></head><body><table>` + tableHeader +
+		row(1, "12-03-2018", "A vs B", "Tax", "s1", "/a.pdf") + `</table></body></html>`
+	page2 := yearPage(row(2, "13-03-2018", "C vs D", "Tax", "s2", "/b.pdf"))
+	broken := `<html><body><p>Service temporarily unavailable</p></body></html>`
+	s := newSite(t, map[int]string{2018: page1, 2019: broken}, map[string]string{"/p2": page2})
+	dir := filepath.Join(t.TempDir(), "html")
+	sc := s.scraper()
+	sc.SaveHTMLDir = dir
+	sc.PersistFunc = func(int, []Judgment) error { return nil }
+	start := time.Now()
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.ScrapeYear(2019, t.TempDir()); !errors.Is(err, ErrNoJudgments) {
+		t.Fatalf("broken page: err = %v, want ErrNoJudgments", err)
+	}
+
+	for _, tc := range []struct {
+		name, body, requestURL, finalURL string
+	}{
+		{"2018", page1, s.URL + "/judgments/?judgment_year=2018", s.URL + "/judgments/?judgment_year=2018"},
+		{"2018-p2", page2, s.URL + "/p2", s.URL + "/p2"},
+		{"2019", broken, s.URL + "/judgments/?judgment_year=2019", s.URL + "/judgments/?judgment_year=2019"},
+	} {
+		body, err := os.ReadFile(filepath.Join(dir, tc.name+".html"))
+		if err != nil || string(body) != tc.body {
+			t.Errorf("%s.html = %q, %v; want the page as served", tc.name, body, err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, tc.name+".meta.json"))
+		if err != nil {
+			t.Errorf("%s.meta.json: %v", tc.name, err)
+			continue
+		}
+		var meta cacheMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			t.Fatal(err)
+		}
+		if meta.RequestURL != tc.requestURL || meta.URL != tc.finalURL {
+			t.Errorf("%s meta URLs = %q, %q; want %q, %q", tc.name, meta.RequestURL, meta.URL, tc.requestURL, tc.finalURL)
+		}
+		if meta.FetchedAt.Before(start.Add(-time.Second)) || meta.FetchedAt.After(time.Now()) {
+			t.Errorf("%s fetched_at = %v, want the time of the fetch", tc.name, meta.FetchedAt)
+		}
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) > 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
@@ -0,0 +1,43 @@
+package scraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortByDate sorts judgments by judgment date, oldest first. Judgments with an
+// empty or unparseable date are placed last, keeping their relative order.
+func SortByDate(judgments []Judgment) {
+	slices.SortStableFunc(judgments, func(a, b Judgment) int {
+		ta, okA := judgmentTime(a)
+		tb, okB := judgmentTime(b)
+		switch {
+		case okA && okB:
+			return ta.Compare(tb)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return 0
+	})
+}
+
+// SortByCauseTitle sorts judgments by cause title, case-insensitively.
+// Judgments with an empty cause title are placed last.
+func SortByCauseTitle(judgments []Judgment) {
+	slices.SortStableFunc(judgments, func(a, b Judgment) int {
+		ka := strings.ToLower(strings.TrimSpace(a.CauseTitleCaseNo))
+		kb := strings.ToLower(strings.TrimSpace(b.CauseTitleCaseNo))
+		switch {
+		case ka == "" && kb == "":
+			return 0
+		case ka == "":
+			return 1
+		case kb == "":
+			return -1
+		}
+		return cmp.Compare(ka, kb)
+	})
+}
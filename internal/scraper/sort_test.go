@@ -0,0 +1,65 @@
+package scraper
+
+import (
+	"slices"
+	"testing"
+)
+
+// summaries returns the JudgmentSummary of each judgment, the tests' labels.
+func summaries(js []Judgment) []string {
+	var out []string
+	for _, j := range js {
+		out = append(out, j.JudgmentSummary)
+	}
+	return out
+}
+
+func TestSortByDate(t *testing.T) {
+	js := []Judgment{
+		{DateOfJudgment: "not a date", JudgmentSummary: "bad1"},
+		{DateOfJudgment: "13-03-2018", JudgmentSummary: "mar13"},
+		{DateOfJudgment: "", JudgmentSummary: "empty"},
+		{DateOfJudgment: "12-03-2018", JudgmentSummary: "tie1"},
+		{DateOfJudgment: "01-01-2017", JudgmentSummary: "2017"},
+		{DateOfJudgment: "12-03-2018", JudgmentSummary: "tie2"},
+		{DateOfJudgment: "31-02-2018", JudgmentSummary: "bad2"},
+		// ParsedDate wins over an unparseable DateOfJudgment
+		{DateOfJudgment: "March 2018", ParsedDate: mustDate(t, "2018-03-01"), JudgmentSummary: "mar1"},
+		{DateOfJudgment: "12-03-2018", JudgmentSummary: "tie3"},
+	}
+	SortByDate(js)
+	want := []string{"2017", "mar1", "tie1", "tie2", "tie3", "mar13", "bad1", "empty", "bad2"}
+	if got := summaries(js); !slices.Equal(got, want) {
+		t.Errorf("SortByDate order %q, want %q", got, want)
+	}
+	// sorting is stable, so sorting again changes nothing
+	SortByDate(js)
+	if got := summaries(js); !slices.Equal(got, want) {
+		t.Errorf("SortByDate of a sorted slice reordered it to %q", got)
+	}
+	SortByDate(nil)
+}
+
+func TestSortByCauseTitle(t *testing.T) {
+	js := []Judgment{
+		{CauseTitleCaseNo: "", JudgmentSummary: "empty1"},
+		{CauseTitleCaseNo: "state vs b", JudgmentSummary: "state1"},
+		{CauseTitleCaseNo: "Alpha vs State", JudgmentSummary: "alpha"},
+		{CauseTitleCaseNo: "   ", JudgmentSummary: "blank"},
+		{CauseTitleCaseNo: "State vs B", JudgmentSummary: "state2"},
+		{CauseTitleCaseNo: "  beta vs State", JudgmentSummary: "beta"},
+		{CauseTitleCaseNo: "STATE VS B ", JudgmentSummary: "state3"},
+		{CauseTitleCaseNo: "", JudgmentSummary: "empty2"},
+	}
+	SortByCauseTitle(js)
+	// titles equal but for case and surrounding space tie and keep their
+	// order, as do the empty ones, last
+	want := []string{"alpha", "beta", "state1", "state2", "state3", "empty1", "blank", "empty2"}
+	if got := summaries(js); !slices.Equal(got, want) {
+		t.Errorf("SortByCauseTitle order %q, want %q", got, want)
+	}
+	SortByCauseTitle(js)
+	if got := summaries(js); !slices.Equal(got, want) {
+		t.Errorf("SortByCauseTitle of a sorted slice reordered it to %q", got)
+	}
+}
@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxSpan caps colspan and rowspan values so that a malformed attribute
+// cannot blow up a row.
+const maxSpan = 50
+
+// cellSpan returns the cell's colspan or rowspan attribute, 1 when absent
+// or invalid.
+func cellSpan(cell *goquery.Selection, attr string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(cell.AttrOr(attr, "1")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return min(n, maxSpan)
+}
+
+// spanGrid lays table rows out as the browser would: a cell with colspan n
+// fills n columns, and one with rowspan n also fills its columns in the n-1
+// following rows.
+type spanGrid struct {
+	// pending maps a column index to the cell still spanning down into it
+	pending map[int]pendingCell
+}
+
+type pendingCell struct {
+	cell *goquery.Selection
+	rows int
+}
+
+// expand returns the row's cells with spanned columns filled in, so that
+// the i-th cell of the result is the one in column i. A cell spanning
+// several columns appears once per column. distinct is the number of
+// different cells in the result.
+func (g *spanGrid) expand(cells *goquery.Selection) (row *goquery.Selection, distinct int) {
+	if g.pending == nil {
+		g.pending = map[int]pendingCell{}
+	}
+	row = cells.Slice(0, 0)
+	// row must not share its backing array with cells
+	row.Nodes = nil
+	// carry fills the next column from a cell spanning down into it
+	carry := func() bool {
+		col := len(row.Nodes)
+		p, ok := g.pending[col]
+		if !ok {
+			return false
+		}
+		row.Nodes = append(row.Nodes, p.cell.Nodes[0])
+		distinct++
+		if p.rows--; p.rows == 0 {
+			delete(g.pending, col)
+		} else {
+			g.pending[col] = p
+		}
+		return true
+	}
+	cells.Each(func(_ int, cell *goquery.Selection) {
+		for carry() {
+		}
+		rows := cellSpan(cell, "rowspan")
+		for range cellSpan(cell, "colspan") {
+			if rows > 1 {
+				g.pending[len(row.Nodes)] = pendingCell{cell: cell, rows: rows - 1}
+			}
+			row.Nodes = append(row.Nodes, cell.Nodes[0])
+		}
+		distinct++
+	})
+	for carry() {
+	}
+	return row, distinct
+}
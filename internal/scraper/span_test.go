@@ -0,0 +1,38 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpannedCells(t *testing.T) {
+	page := `<html><body><table>` + tableHeader +
+		`<tr><td>1</td><td>12-03-2018</td><td>A vs B</td><td rowspan="2">Tax</td><td>s1</td><td><a href="/a.pdf">PDF</a></td></tr>` +
+		`<tr><td>2</td><td>13-03-2018</td><td>C vs D</td><td>s2</td><td><a href="/c.pdf">PDF</a></td></tr>` +
+		`<tr><td>3</td><td colspan="2">14-03-2018</td><td>Civil</td><td>s3</td><td><a href="/e.pdf">PDF</a></td></tr>` +
+		`<tr><td>4</td><td>15-03-2018</td><td>G vs H</td><td>Civil</td><td>s4</td><td rowspan="abc"><a href="/g.pdf">PDF</a></td></tr>` +
+		`<tr><td colspan="6">Judgments of March</td></tr>` +
+		`<tr><td>5</td><td>broken</td></tr>` +
+		`</table></body></html>`
+	js, stats, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/judgments/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(js) != 4 {
+		t.Fatalf("parsed %d judgments, want 4: %+v", len(js), js)
+	}
+	for i, want := range []Judgment{
+		{DateOfJudgment: "12-03-2018", CauseTitleCaseNo: "A vs B", Subject: "Tax", JudgmentSummary: "s1", PDFLink: "https://example.org/a.pdf"},
+		{DateOfJudgment: "13-03-2018", CauseTitleCaseNo: "C vs D", Subject: "Tax", JudgmentSummary: "s2", PDFLink: "https://example.org/c.pdf"},
+		{DateOfJudgment: "14-03-2018", CauseTitleCaseNo: "14-03-2018", Subject: "Civil", JudgmentSummary: "s3", PDFLink: "https://example.org/e.pdf"},
+		{DateOfJudgment: "15-03-2018", CauseTitleCaseNo: "G vs H", Subject: "Civil", JudgmentSummary: "s4", PDFLink: "https://example.org/g.pdf"},
+	} {
+		j := js[i]
+		if j.DateOfJudgment != want.DateOfJudgment || j.CauseTitleCaseNo != want.CauseTitleCaseNo || j.Subject != want.Subject || j.JudgmentSummary != want.JudgmentSummary || j.PDFLink != want.PDFLink {
+			t.Errorf("judgment %d = %+v, want %+v", i, j, want)
+		}
+	}
+	if stats.IrregularRows != 2 {
+		t.Errorf("IrregularRows = %d, want the notice and the broken row", stats.IrregularRows)
+	}
+}
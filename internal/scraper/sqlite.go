@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	// pure-Go SQLite driver, registered as "sqlite"; no cgo needed
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteFileName is the database written in outDir by FormatSQLite when
+// Scraper.DBPath is empty.
+const SQLiteFileName = "sci_judgments.db"
+
+// dbPath returns the database FormatSQLite writes for outDir.
+func (sc *Scraper) dbPath(outDir string) string {
+	if sc.DBPath != "" {
+		return sc.DBPath
+	}
+	return filepath.Join(outDir, SQLiteFileName)
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS judgments (
+	judgment_key        TEXT PRIMARY KEY,
+	year                INTEGER NOT NULL,
+	judgment_date       TEXT NOT NULL,
+	parsed_date         TEXT,
+	cause_title_case_no TEXT NOT NULL,
+	subject             TEXT NOT NULL,
+	judgment_summary    TEXT NOT NULL,
+	pdf_link            TEXT NOT NULL
+)`
+
+const sqliteUpsert = `
+INSERT INTO judgments (judgment_key, year, judgment_date, parsed_date, cause_title_case_no, subject, judgment_summary, pdf_link)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (judgment_key) DO UPDATE SET
+	year = excluded.year,
+	judgment_date = excluded.judgment_date,
+	parsed_date = excluded.parsed_date,
+	cause_title_case_no = excluded.cause_title_case_no,
+	subject = excluded.subject,
+	judgment_summary = excluded.judgment_summary,
+	pdf_link = excluded.pdf_link`
+
+// sqliteMu serializes writers in this process; concurrent workers share one
+// database file.
+var sqliteMu sync.Mutex
+
+// WriteSQLite upserts judgments into the judgments table of the SQLite
+// database at dbPath, creating both if needed. Rows are keyed on the same
+// identity as Dedup (the normalized PDF link, or the date and cause title),
+// so re-running a year updates its rows instead of duplicating them.
+func WriteSQLite(dbPath string, judgments []Judgment) error {
+	sqliteMu.Lock()
+	defer sqliteMu.Unlock()
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(sqliteUpsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, j := range judgments {
+		var parsed any
+		if !j.ParsedDate.IsZero() {
+			parsed = j.ParsedDate.Format(time.DateOnly)
+		}
+		if _, err := stmt.Exec(dedupKey(j), j.Year, j.DateOfJudgment, parsed, j.CauseTitleCaseNo, j.Subject, j.JudgmentSummary, j.PDFLink); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// sqliteHasYear reports whether the database at dbPath has rows for year.
+func sqliteHasYear(dbPath string, year int) bool {
+	if _, err := os.Stat(dbPath); err != nil {
+		return false
+	}
+	sqliteMu.Lock()
+	defer sqliteMu.Unlock()
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM judgments WHERE year = ?`, year).Scan(&n); err != nil {
+		return false
+	}
+	return n > 0
+}
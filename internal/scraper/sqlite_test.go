@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// countRows returns the number of rows in the judgments table at dbPath,
+// and how many of them hold summary.
+func countRows(t *testing.T, dbPath, summary string) (rows, matching int) {
+	t.Helper()
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.QueryRow(`SELECT count(*), count(CASE WHEN judgment_summary = ? THEN 1 END) FROM judgments`, summary).Scan(&rows, &matching); err != nil {
+		t.Fatal(err)
+	}
+	return rows, matching
+}
+
+func TestWriteSQLiteUpserts(t *testing.T) {
+	dir := t.TempDir()
+	sc := &Scraper{Format: FormatSQLite}
+	js := syntheticJudgments(5)
+	for i := range js {
+		js[i].Year = 2018
+	}
+	if err := sc.WriteYear(dir, 2018, js); err != nil {
+		t.Fatal(err)
+	}
+	js[0].JudgmentSummary = "edited"
+	if err := sc.WriteYear(dir, 2018, js); err != nil {
+		t.Fatal(err)
+	}
+	db := filepath.Join(dir, SQLiteFileName)
+	if rows, edited := countRows(t, db, "edited"); rows != 5 || edited != 1 {
+		t.Errorf("after re-inserting the year: %d rows, %d edited; want 5, 1", rows, edited)
+	}
+	if !sc.OutputExists(dir, 2018) || sc.OutputExists(dir, 2019) {
+		t.Error("OutputExists does not follow the years in the database")
+	}
+}
+
+func TestSQLiteDBPath(t *testing.T) {
+	out, elsewhere := t.TempDir(), t.TempDir()
+	db := filepath.Join(elsewhere, "judgments.db")
+	sc := &Scraper{Format: FormatSQLite, DBPath: db}
+	js := syntheticJudgments(3)
+	for i := range js {
+		js[i].Year = 2018
+	}
+	if err := sc.WriteYear(out, 2018, js); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := countRows(t, db, ""); rows != 3 {
+		t.Errorf("DBPath holds %d rows, want 3", rows)
+	}
+	if _, err := os.Stat(filepath.Join(out, SQLiteFileName)); !os.IsNotExist(err) {
+		t.Errorf("a database was written in the output directory: %v", err)
+	}
+	if !sc.OutputExists(out, 2018) {
+		t.Error("OutputExists does not look in DBPath")
+	}
+	if (&Scraper{Format: FormatSQLite}).OutputExists(out, 2018) {
+		t.Error("OutputExists found the year without DBPath")
+	}
+}
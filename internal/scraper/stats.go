@@ -0,0 +1,91 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Stats are parse diagnostics for one year's page, useful for noticing when
+// the site's table layout changes.
+type Stats struct {
+	// FinalURL is the URL the page was served from after any redirects, the
+	// base for its relative links.
+	FinalURL string
+	// Pages is the number of result pages parsed.
+	Pages int
+	// Rows is the number of <tr> elements seen, including the headers.
+	Rows int
+	// SkippedEmpty counts data rows dropped for having no cells or only
+	// empty fields.
+	SkippedEmpty int
+	// IrregularRows counts data rows dropped because their cells don't fit
+	// the table's columns, such as notices spanning the whole table.
+	IrregularRows int
+	// MissingPDF counts parsed rows without a PDF link.
+	MissingPDF int
+	// HeaderDetected reports whether the table had a header row, on any
+	// page.
+	HeaderDetected bool
+	// Parsed counts rows read as judgments, before any filtering.
+	Parsed int
+	// Advertised is the total the page claims to list ("Showing 42
+	// judgments"), or 0 when it shows none.
+	Advertised int
+	// FailedPage is the number of the later result page that still failed
+	// after Scraper.PageRetries, cutting the year short so that its
+	// judgments are partial, or 0.
+	FailedPage int
+}
+
+// advertisedCount matches totals such as "Showing 42 judgments", "Total
+// records: 42" and "Showing 1 to 10 of 42 entries". Like emptyState it has
+// no trailing \b, as the document's text runs the total into whatever
+// follows ("42 entriesPrevious").
+var advertisedCount = regexp.MustCompile(`(?i)(?:\bof|\bshowing)\s+(\d{1,6})\s+(?:judgments?|records?|results?|entries)|\btotal\s+(?:judgments|records|entries)\s*:?\s*(\d{1,6})(?:\D|$)`)
+
+// findAdvertised returns the first total advertised in text, or 0.
+func findAdvertised(text string) int {
+	m := advertisedCount.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1] + m[2])
+	return n
+}
+
+// emptyState matches the site's messages for a listing without results,
+// such as "No records found" and "No data available in table". There is no
+// leading \b: the document's text runs cells together ("TitleNo records").
+var emptyState = regexp.MustCompile(`(?i)no\s+(?:records?|judgments?|results?|data|entries|matching\s+records)\s+(?:were\s+)?(?:found|available)\b`)
+
+// isEmptyPage reports whether text carries an empty-state message.
+func isEmptyPage(text string) bool {
+	return emptyState.MatchString(text)
+}
+
+// CountMismatch reports whether the page advertised a total other than the
+// number of rows parsed. The total may cover other pages, so a mismatch is
+// a hint rather than an error.
+func (s Stats) CountMismatch() bool {
+	return s.Advertised > 0 && s.Advertised != s.Parsed
+}
+
+// suspiciousSkipRatio is the share of empty data rows above which a parse is
+// logged as suspicious.
+const suspiciousSkipRatio = 0.5
+
+// dataRows is the number of rows that were not a header, taking each page
+// to have one.
+func (s Stats) dataRows() int {
+	if s.HeaderDetected && s.Rows > 0 {
+		return max(s.Rows-max(s.Pages, 1), 0)
+	}
+	return s.Rows
+}
+
+// suspicious reports whether more than suspiciousSkipRatio of the data rows
+// were skipped as empty.
+func (s Stats) suspicious() bool {
+	n := s.dataRows()
+	return n > 0 && float64(s.SkippedEmpty)/float64(n) > suspiciousSkipRatio
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStats(t *testing.T) {
+	page := yearPage(
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"),
+		`<tr><td></td><td> </td><td></td><td></td><td></td><td></td></tr>`,
+		`<tr></tr>`,
+		row(2, "13-03-2018", "C vs D", "Tax", "no link", ""),
+		row(3, "14-03-2018", "E vs F", "Tax", "s", "/c.pdf"),
+	)
+	js, stats, err := (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(page), "https://example.org/", 2018)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Stats{FinalURL: "https://example.org/", Pages: 1, Rows: 6, SkippedEmpty: 2, MissingPDF: 1, HeaderDetected: true, Parsed: 3}
+	if stats != want {
+		t.Errorf("stats = %+v\nwant    %+v", stats, want)
+	}
+	if len(js) != 3 {
+		t.Errorf("parsed %d judgments, want 3", len(js))
+	}
+	if stats.suspicious() {
+		t.Error("2 empty rows of 5 are taken as a layout change")
+	}
+
+	blank := `<tr><td></td><td></td><td></td><td></td><td></td><td></td></tr>`
+	_, stats, err = (&Scraper{Logger: discard}).ParseHTML(strings.NewReader(yearPage(blank, blank, `<tr><td>notice</td></tr>`)), "https://example.org/", 2018)
+	if err == nil || stats.SkippedEmpty != 2 || stats.IrregularRows != 1 || stats.Parsed != 0 || !stats.suspicious() {
+		t.Errorf("a page of empty rows: stats %+v, %v", stats, err)
+	}
+}
+
+func TestFindAdvertised(t *testing.T) {
+	for text, want := range map[string]int{
+		"Showing 42 judgments":                        42,
+		"Showing 1 to 10 of 42 entriesPreviousNext":   42,
+		"Total records: 120":                          120,
+		"Total Judgments 7Sr.No":                      7,
+		"Judgments of 2018":                           0,
+		"Total records: 1234567":                      0,
+		"Landmark judgments delivered in March 2018.": 0,
+	} {
+		if got := findAdvertised(text); got != want {
+			t.Errorf("findAdvertised(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestCountMismatchWarning(t *testing.T) {
+	rows := []string{
+		row(1, "12-03-2018", "A vs B", "Tax", "s", "/a.pdf"),
+		row(2, "13-03-2018", "C vs D", "Tax", "s", "/b.pdf"),
+	}
+	for _, tc := range []struct {
+		advertised string
+		want       int
+		warned     bool
+	}{
+		{"Showing 1 to 2 of 5 entries", 5, true},
+		{"Showing 1 to 2 of 2 entries", 2, false},
+		{"", 0, false},
+	} {
+		page := `<html><body><div class="info">` + tc.advertised + `</div><table>` + tableHeader + strings.Join(rows, "") + `</table><a>Next</a></body></html>`
+		logger := &captureLogger{}
+		sc := &Scraper{Logger: logger}
+		js, stats, err := sc.ParseHTML(strings.NewReader(page), "https://example.org/", 2018)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(js) != 2 || stats.Advertised != tc.want || stats.CountMismatch() != tc.warned {
+			t.Errorf("%q: %d judgments, Advertised %d, CountMismatch %t; want 2, %d, %t", tc.advertised, len(js), stats.Advertised, stats.CountMismatch(), tc.want, tc.warned)
+		}
+		if got := logger.contains("page advertises 5 judgments but 2 were parsed"); got != tc.warned {
+			t.Errorf("%q: warning logged = %t, want %t (%q)", tc.advertised, got, tc.warned, logger.lines)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONArrayWriter writes judgments one at a time as an indented JSON array,
+// laid out exactly like EncodeJudgments, so that large outputs need not be
+// held in memory. Call Close to finish the array.
+type JSONArrayWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+	enc *json.Encoder
+	n   int
+}
+
+// NewJSONArrayWriter returns a JSONArrayWriter writing to w.
+func NewJSONArrayWriter(w io.Writer) *JSONArrayWriter {
+	aw := &JSONArrayWriter{w: w}
+	aw.enc = json.NewEncoder(&aw.buf)
+	aw.enc.SetIndent("  ", "  ")
+	aw.enc.SetEscapeHTML(false)
+	return aw
+}
+
+// Write appends j to the array.
+func (aw *JSONArrayWriter) Write(j Judgment) error {
+	aw.buf.Reset()
+	if aw.n == 0 {
+		aw.buf.WriteString("[\n  ")
+	} else {
+		aw.buf.WriteString(",\n  ")
+	}
+	if err := aw.enc.Encode(j); err != nil {
+		return err
+	}
+	// Encode ends the value with a newline that belongs after the comma
+	aw.buf.Truncate(aw.buf.Len() - 1)
+	aw.n++
+	_, err := aw.w.Write(aw.buf.Bytes())
+	return err
+}
+
+// Close terminates the array. It does not close the underlying writer.
+func (aw *JSONArrayWriter) Close() error {
+	end := "\n]\n"
+	if aw.n == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(aw.w, end)
+	return err
+}
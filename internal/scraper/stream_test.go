@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// syntheticJudgments returns n distinct judgments with characters that
+// need escaping.
+func syntheticJudgments(n int) []Judgment {
+	js := make([]Judgment, n)
+	for i := range js {
+		js[i] = Judgment{
+			DateOfJudgment:   fmt.Sprintf("%02d-01-2018", i%28+1),
+			CauseTitleCaseNo: fmt.Sprintf("P%d vs R%d", i, i),
+			Subject:          "Tax & Revenue",
+			JudgmentSummary:  fmt.Sprintf("summary \"%d\"\nline two", i),
+			PDFLink:          fmt.Sprintf("https://example.org/view-pdf/?a=%d&b=2", i),
+		}
+	}
+	return js
+}
+
+func TestJSONArrayWriterMatchesEncodeJudgments(t *testing.T) {
+	for _, n := range []int{0, 1, 300} {
+		js := syntheticJudgments(n)
+		var streamed, whole bytes.Buffer
+		aw := NewJSONArrayWriter(&streamed)
+		for _, j := range js {
+			if err := aw.Write(j); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := aw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := EncodeJudgments(&whole, js); err != nil {
+			t.Fatal(err)
+		}
+		if n > 0 && streamed.String() != whole.String() {
+			t.Errorf("n=%d: streamed output differs from EncodeJudgments", n)
+		}
+		var back []Judgment
+		if err := json.Unmarshal(streamed.Bytes(), &back); err != nil {
+			t.Fatalf("n=%d: streamed output does not parse: %v", n, err)
+		}
+		if n > 0 && !reflect.DeepEqual(back, js) {
+			t.Errorf("n=%d: round trip changed the judgments", n)
+		}
+	}
+}
+
+func TestMergedWriterConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	mw := NewMergedWriter(dir)
+	var wg sync.WaitGroup
+	for y := 2016; y < 2020; y++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := mw.Add(y, syntheticJudgments(50)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, MergedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back []Judgment
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("merged file does not parse: %v", err)
+	}
+	perYear := map[int]int{}
+	for _, j := range back {
+		perYear[j.Year]++
+	}
+	if want := map[int]int{2016: 50, 2017: 50, 2018: 50, 2019: 50}; !reflect.DeepEqual(perYear, want) {
+		t.Errorf("judgments per year = %v, want %v", perYear, want)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the merged file", len(entries))
+	}
+}
+
+func TestMergedWriterAbortKeepsEarlierFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteMerged(dir, map[int][]Judgment{2018: syntheticJudgments(2)}); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(filepath.Join(dir, MergedFileName))
+	mw := NewMergedWriter(dir)
+	if err := mw.Add(2019, syntheticJudgments(3)); err != nil {
+		t.Fatal(err)
+	}
+	mw.Abort()
+	if err := mw.Close(); err == nil {
+		t.Error("Close after Abort succeeded")
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, MergedFileName))
+	if !bytes.Equal(before, after) {
+		t.Error("an aborted merge replaced the earlier file")
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(names) != 1 {
+		t.Errorf("files left behind: %v", names)
+	}
+}
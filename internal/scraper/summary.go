@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// YearStatus is the outcome of one year in a run.
+type YearStatus string
+
+const (
+	YearOK      YearStatus = "ok"
+	YearFailed  YearStatus = "failed"
+	YearSkipped YearStatus = "skipped"
+	// YearPartial is a year written with only some of its judgments, its
+	// error wrapping ErrPartial; it should be re-run.
+	YearPartial YearStatus = "partial"
+)
+
+// YearSummary is one year's line in a RunSummary.
+type YearSummary struct {
+	Year      int
+	Status    YearStatus
+	Judgments int
+	Duration  time.Duration
+	Err       error
+}
+
+// RunSummary collects per-year outcomes of a multi-year scrape. It is safe
+// for concurrent use; years may be recorded in any order. The zero value is
+// ready to use.
+type RunSummary struct {
+	mu    sync.Mutex
+	years map[int]YearSummary
+}
+
+// Record adds or replaces the outcome of ys.Year.
+func (r *RunSummary) Record(ys YearSummary) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.years == nil {
+		r.years = map[int]YearSummary{}
+	}
+	r.years[ys.Year] = ys
+}
+
+// Years returns the recorded outcomes sorted by year.
+func (r *RunSummary) Years() []YearSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]YearSummary, 0, len(r.years))
+	for _, ys := range r.years {
+		out = append(out, ys)
+	}
+	slices.SortFunc(out, func(a, b YearSummary) int { return a.Year - b.Year })
+	return out
+}
+
+// Count returns the number of years recorded with status.
+func (r *RunSummary) Count(status YearStatus) int {
+	n := 0
+	for _, ys := range r.Years() {
+		if ys.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Judgments returns the number of judgments over all recorded years.
+func (r *RunSummary) Judgments() int {
+	n := 0
+	for _, ys := range r.Years() {
+		n += ys.Judgments
+	}
+	return n
+}
+
+// Print writes the summary as a table, one year per line, followed by the
+// totals. Partial years are counted among them only when there are some.
+func (r *RunSummary) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tSTATUS\tJUDGMENTS\tDURATION\tERROR")
+	for _, ys := range r.Years() {
+		msg := ""
+		if ys.Err != nil {
+			msg = ys.Err.Error()
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\n", ys.Year, ys.Status, ys.Judgments, ys.Duration.Round(time.Millisecond), msg)
+	}
+	counts := fmt.Sprintf("%d ok, %d failed, %d skipped", r.Count(YearOK), r.Count(YearFailed), r.Count(YearSkipped))
+	if n := r.Count(YearPartial); n > 0 {
+		counts += fmt.Sprintf(", %d partial", n)
+	}
+	fmt.Fprintf(tw, "total\t%s\t%d\t\t\n", counts, r.Judgments())
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunSummary(t *testing.T) {
+	var r RunSummary
+	var wg sync.WaitGroup
+	// workers finish out of order
+	for _, ys := range []YearSummary{
+		{Year: 2019, Status: YearFailed, Duration: time.Second, Err: errors.New("HTTP 503")},
+		{Year: 2017, Status: YearSkipped},
+		{Year: 2018, Status: YearOK, Judgments: 12, Duration: 2 * time.Second},
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Record(ys)
+		}()
+	}
+	wg.Wait()
+	r.Record(YearSummary{Year: 2016, Status: YearFailed})
+	r.Record(YearSummary{Year: 2016, Status: YearOK, Judgments: 3})
+
+	var years []int
+	for _, ys := range r.Years() {
+		years = append(years, ys.Year)
+	}
+	if len(years) != 4 || years[0] != 2016 || years[3] != 2019 {
+		t.Errorf("Years() = %v, want 2016..2019 in order", years)
+	}
+	if ok, failed, skipped := r.Count(YearOK), r.Count(YearFailed), r.Count(YearSkipped); ok != 2 || failed != 1 || skipped != 1 {
+		t.Errorf("counts = %d ok, %d failed, %d skipped; want 2, 1, 1", ok, failed, skipped)
+	}
+	if n := r.Judgments(); n != 15 {
+		t.Errorf("Judgments() = %d, want 15", n)
+	}
+
+	var b strings.Builder
+	if err := r.Print(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Print wrote %d lines, want a header, 4 years and totals:\n%s", len(lines), b.String())
+	}
+	for i, want := range [][]string{
+		{"YEAR", "STATUS", "JUDGMENTS"},
+		{"2016", "ok", "3"},
+		{"2017", "skipped", "0"},
+		{"2018", "ok", "12", "2s"},
+		{"2019", "failed", "0", "1s", "HTTP 503"},
+		{"total", "2 ok, 1 failed, 1 skipped", "15"},
+	} {
+		for _, w := range want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("line %d %q lacks %q", i, lines[i], w)
+			}
+		}
+	}
+}
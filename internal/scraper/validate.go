@@ -0,0 +1,29 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidJudgments is wrapped by the errors of ValidateJudgments.
+var ErrInvalidJudgments = errors.New("invalid judgments")
+
+// ValidateJudgments checks the invariants every written file should meet:
+// there is at least one judgment, and each has a date, a cause title or a
+// PDF link. A parser regression that empties every field fails here rather
+// than producing a file of blank records.
+func ValidateJudgments(judgments []Judgment) error {
+	if len(judgments) == 0 {
+		return fmt.Errorf("%w: no judgments", ErrInvalidJudgments)
+	}
+	var errs []error
+	for i, j := range judgments {
+		if j.DateOfJudgment == "" && j.CauseTitleCaseNo == "" && j.PDFLink == "" {
+			errs = append(errs, fmt.Errorf("judgment %d has no date, cause title or PDF link", i))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidJudgments, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateJudgments(t *testing.T) {
+	valid := []Judgment{
+		{DateOfJudgment: "12-03-2018"},
+		{CauseTitleCaseNo: "A vs B"},
+		{PDFLink: "https://example.org/a.pdf", Subject: "Tax"},
+	}
+	if err := ValidateJudgments(valid); err != nil {
+		t.Errorf("valid judgments: %v", err)
+	}
+	blank := append(valid, Judgment{Subject: "Tax", JudgmentSummary: "held"})
+	err := ValidateJudgments(blank)
+	if !errors.Is(err, ErrInvalidJudgments) || !strings.Contains(err.Error(), "judgment 3 ") {
+		t.Errorf("a record without date, cause title or PDF link: %v", err)
+	}
+	if err := ValidateJudgments(nil); !errors.Is(err, ErrInvalidJudgments) {
+		t.Errorf("no judgments: %v", err)
+	}
+}
+
+func TestStrictAbortsWrite(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		strict     bool
+		judgments  []Judgment
+		wantErr    bool
+		wantOutput bool
+	}{
+		{"strict valid", true, []Judgment{{DateOfJudgment: "12-03-2018"}}, false, true},
+		{"strict blank record", true, []Judgment{{Subject: "Tax"}}, true, false},
+		{"strict empty", true, nil, true, false},
+		{"lenient blank record", false, []Judgment{{Subject: "Tax"}}, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sc := &Scraper{Logger: discard, Strict: tc.strict}
+			err := sc.Persist(context.Background(), 2018, dir, tc.judgments)
+			if (err != nil) != tc.wantErr || tc.wantErr && !errors.Is(err, ErrInvalidJudgments) {
+				t.Errorf("Persist error = %v, want error %t", err, tc.wantErr)
+			}
+			_, statErr := os.Stat(filepath.Join(dir, YearFileName(2018, FormatJSON)))
+			if wrote := statErr == nil; wrote != tc.wantOutput {
+				t.Errorf("year file written = %t, want %t", wrote, tc.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultVerifyConcurrency is the number of PDF links a year checks at once
+// when Scraper.VerifyConcurrency is zero.
+const DefaultVerifyConcurrency = 4
+
+// verifyPDFs sets PDFOK on every judgment with a PDF link, checking
+// VerifyConcurrency links at once, and logs how many links are broken. A
+// broken link is only marked, never an error; when ctx is cancelled the
+// judgments not yet checked are left unmarked.
+func (sc *Scraper) verifyPDFs(ctx context.Context, year int, judgments []Judgment) {
+	workers := sc.VerifyConcurrency
+	if workers <= 0 {
+		workers = DefaultVerifyConcurrency
+	}
+	var broken atomic.Int32
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each worker only touches the judgments it is sent
+			for i := range jobs {
+				ok := sc.pdfReachable(ctx, judgments[i].PDFLink)
+				if ctx.Err() != nil {
+					continue
+				}
+				judgments[i].PDFOK = &ok
+				if !ok {
+					broken.Add(1)
+				}
+			}
+		}()
+	}
+feed:
+	for i := range judgments {
+		if judgments[i].PDFLink == "" {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if n := broken.Load(); n > 0 {
+		sc.warnf("warning: year %d: %d broken PDF links", year, n)
+	}
+}
+
+// pdfReachable reports whether link serves a PDF. It tries a HEAD request
+// and falls back to a GET of the first bytes for servers that reject HEAD
+// or don't declare the content type.
+func (sc *Scraper) pdfReachable(ctx context.Context, link string) bool {
+	resp, err := sc.probe(ctx, http.MethodHead, link)
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && isPDFType(resp.Header.Get("Content-Type")) {
+			return true
+		}
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			return false
+		}
+	}
+	resp, err = sc.probe(ctx, http.MethodGet, link)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+	if isPDFType(resp.Header.Get("Content-Type")) {
+		return true
+	}
+	magic := make([]byte, 5)
+	n, _ := io.ReadFull(resp.Body, magic)
+	return string(magic[:n]) == "%PDF-"
+}
+
+// probe issues a rate-limited request for link, asking GETs for only the
+// first bytes of the body.
+func (sc *Scraper) probe(ctx context.Context, method, link string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	sc.setHeaders(req)
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-1023")
+	}
+	if err := sc.wait(ctx); err != nil {
+		return nil, err
+	}
+	return sc.do(req)
+}
+
+func isPDFType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/pdf"
+}
@@ -0,0 +1,102 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVerifyPDFs(t *testing.T) {
+	s := newSite(t, map[int]string{2018: yearPage(
+		row(1, "12-03-2018", "ok", "Tax", "s", "/ok.pdf"),
+		row(2, "12-03-2018", "missing", "Tax", "s", "/missing.pdf"),
+		row(3, "12-03-2018", "sniffed", "Tax", "s", "/view-pdf/?id=3"),
+		row(4, "12-03-2018", "error page", "Tax", "s", "/view-pdf-error/?id=4"),
+		row(5, "12-03-2018", "no link", "Tax", "s", ""),
+	)}, map[string]string{
+		"/ok.pdf":          pdfBody,
+		"/view-pdf/":       pdfBody,
+		"/view-pdf-error/": "<html>session expired</html>",
+	})
+	sc := s.scraper()
+	sc.VerifyPDFs = true
+	logger := &captureLogger{}
+	sc.Logger = logger
+	got := map[string]*bool{}
+	sc.PersistFunc = func(_ int, js []Judgment) error {
+		for _, j := range js {
+			got[j.CauseTitleCaseNo] = j.PDFOK
+		}
+		return nil
+	}
+	if err := sc.ScrapeYear(2018, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	for title, want := range map[string]bool{"ok": true, "missing": false, "sniffed": true, "error page": false} {
+		if got[title] == nil || *got[title] != want {
+			t.Errorf("%s: PDFOK = %v, want %t", title, got[title], want)
+		}
+	}
+	if got["no link"] != nil {
+		t.Errorf("a judgment without a link was marked %t", *got["no link"])
+	}
+	if n := s.hits("/missing.pdf"); n != 1 {
+		t.Errorf("a 404 on HEAD was retried with GET: %d requests", n)
+	}
+	if !logger.contains("2 broken PDF links") {
+		t.Errorf("logged %q, want the broken link count", logger.lines)
+	}
+}
+
+func TestVerifyPDFsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if r.URL.Path == "/dead.pdf" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	}))
+	defer srv.Close()
+	var js []Judgment
+	for i := range 8 {
+		js = append(js, Judgment{PDFLink: fmt.Sprintf("%s/%d.pdf", srv.URL, i)})
+	}
+	js = append(js, Judgment{PDFLink: srv.URL + "/dead.pdf"}, Judgment{})
+	logger := &captureLogger{}
+	sc := &Scraper{HTTPClient: srv.Client(), Logger: logger, VerifyConcurrency: 3}
+	sc.verifyPDFs(context.Background(), 2018, js)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak > 3 || peak < 2 {
+		t.Errorf("%d checks ran at once, want 2 to VerifyConcurrency 3", peak)
+	}
+	for i, j := range js[:8] {
+		if j.PDFOK == nil || !*j.PDFOK {
+			t.Errorf("judgment %d: PDFOK = %v, want true", i, j.PDFOK)
+		}
+	}
+	if ok := js[8].PDFOK; ok == nil || *ok {
+		t.Errorf("the dead link: PDFOK = %v, want false", ok)
+	}
+	if js[9].PDFOK != nil {
+		t.Error("a judgment without a link was checked")
+	}
+	if !logger.contains("1 broken PDF links") {
+		t.Errorf("logged %q, want the broken link count", logger.lines)
+	}
+}